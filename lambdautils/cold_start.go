@@ -0,0 +1,14 @@
+package lambdautils
+
+import "sync/atomic"
+
+// warm is flipped on the first call to IsColdStart within the process
+var warm atomic.Bool
+
+// IsColdStart returns true on its first call within the process lifetime and
+// false on every call after that. It must be called exactly once per
+// invocation, at the start of the handler, for the result to reflect whether
+// that invocation was a cold start.
+func IsColdStart() bool {
+	return !warm.Swap(true)
+}
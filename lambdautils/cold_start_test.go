@@ -0,0 +1,13 @@
+package lambdautils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsColdStart(t *testing.T) {
+	assert.True(t, IsColdStart())
+	assert.False(t, IsColdStart())
+	assert.False(t, IsColdStart())
+}
@@ -0,0 +1,325 @@
+package lambdautils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/pkg/errors"
+)
+
+// IdempotencyLock manages locking of arbitrary idempotency keys using dynamodb
+// conditional puts with the aws-sdk-go v1 client, see LockConfig for the
+// configuration options.
+type IdempotencyLock struct {
+	LockConfig
+
+	client  dynamodbiface.DynamoDBAPI
+	svcFunc func(client.ConfigProvider) dynamodbiface.DynamoDBAPI
+}
+
+// NewIdempotencyLock returns a new idempotency lock instance to manage dynamodb
+// locking
+func NewIdempotencyLock(region string, table string, ttl int64, retry int64) *IdempotencyLock {
+	lock := new(IdempotencyLock)
+	lock.Region = region
+	lock.Table = table
+	lock.TTL = ttl
+	lock.RetryWait = retry
+	lock.setDefaults()
+
+	return lock
+}
+
+// NewIdempotencyLockFromJson returns a new idempotency lock instance to manage
+// dynamodb locking
+func NewIdempotencyLockFromJson(s string) (*IdempotencyLock, error) {
+	lock := new(IdempotencyLock)
+
+	err := json.Unmarshal([]byte(s), lock)
+	if err != nil {
+		return nil, err
+	}
+
+	err = lock.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	lock.setDefaults()
+
+	return lock, nil
+}
+
+// svc is used internally to assist stubs on dynamodb for testing
+func (lock *IdempotencyLock) svc(p client.ConfigProvider) dynamodbiface.DynamoDBAPI {
+	if lock.svcFunc != nil {
+		return lock.svcFunc(p)
+	}
+
+	return dynamodb.New(p)
+}
+
+// service returns the client set with SetClient, or a new client on a fresh
+// session for the configured region.
+func (lock *IdempotencyLock) service() (dynamodbiface.DynamoDBAPI, error) {
+	if lock.client != nil {
+		return lock.client, nil
+	}
+
+	s, err := session.NewSession(&aws.Config{
+		Region: aws.String(lock.Region),
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed getting session")
+	}
+
+	return lock.svc(s), nil
+}
+
+// retryable returns true if the put error is transient and worth retrying
+func retryable(err error) bool {
+	if aerr, ok := err.(awserr.Error); ok {
+		switch aerr.Code() {
+		case dynamodb.ErrCodeProvisionedThroughputExceededException,
+			dynamodb.ErrCodeRequestLimitExceeded,
+			"ThrottlingException",
+			"Throttling":
+			return true
+		}
+	}
+
+	return strings.Contains(err.Error(), "connection reset by peer")
+}
+
+// putItemInput constructs the input for the given id insertion into dynamodb.
+// It applies a conditional expression that causes failures when the id has
+// already been added but not yet expired.
+func (lock *IdempotencyLock) putItemInput(id string) *dynamodb.PutItemInput {
+	return lock.putItemInputTTL(id, lock.TTL)
+}
+
+// putItemInputTTL constructs the input for the given id insertion into dynamodb
+// expiring after the given ttl (seconds).
+func (lock *IdempotencyLock) putItemInputTTL(id string, ttl int64) *dynamodb.PutItemInput {
+	return lock.putItemInputItem(lock.lockItem(id, ttl))
+}
+
+// putItemInputItem constructs the input for the conditional put of the lock
+// item into dynamodb.
+func (lock *IdempotencyLock) putItemInputItem(item lockItem) *dynamodb.PutItemInput {
+	return &dynamodb.PutItemInput{
+		Item: map[string]*dynamodb.AttributeValue{
+			lock.keyAttribute(): {
+				S: aws.String(item.key),
+			},
+			lock.expireAttribute(): {
+				N: aws.String(item.expire),
+			},
+		},
+		TableName:           aws.String(lock.Table),
+		ConditionExpression: aws.String(lockCondition),
+		ExpressionAttributeNames: map[string]*string{
+			"#key":    aws.String(lock.keyAttribute()),
+			"#expire": aws.String(lock.expireAttribute()),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":cur": {
+				N: aws.String(item.current),
+			},
+		},
+	}
+}
+
+// deleteItemInput constructs the input for removing the given id from dynamodb
+func (lock *IdempotencyLock) deleteItemInput(id string) *dynamodb.DeleteItemInput {
+	return &dynamodb.DeleteItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			lock.keyAttribute(): {
+				S: aws.String(id),
+			},
+		},
+		TableName: aws.String(lock.Table),
+	}
+}
+
+// Acquire returns true if the given key was available and is now locked, and
+// it returns false if it is already locked.
+//
+// Locked is defined as the record being in the configured dynamodb table and
+// not expires.
+func (lock *IdempotencyLock) Acquire(key string) (bool, error) {
+	return lock.AcquireWithTTL(key, lock.TTL)
+}
+
+// AcquireWithTTL is like Acquire but the lock expires after the given ttl
+// (seconds) instead of the configured TTL.
+func (lock *IdempotencyLock) AcquireWithTTL(key string, ttl int64) (bool, error) {
+	if ttl <= 0 {
+		return false, fmt.Errorf("ttl must be positive, received: %v", ttl)
+	}
+
+	return lock.acquire(lock.lockItem(key, ttl))
+}
+
+// acquire conditionally puts the lock item, see LockConfig.put
+func (lock *IdempotencyLock) acquire(item lockItem) (bool, error) {
+	svc, err := lock.service()
+	if err != nil {
+		return false, err
+	}
+
+	return lock.put(lockPutterV1{lock: lock, svc: svc}, item)
+}
+
+// lockPutterV1 puts lock items using the aws-sdk-go v1 client
+type lockPutterV1 struct {
+	lock *IdempotencyLock
+	svc  dynamodbiface.DynamoDBAPI
+}
+
+func (p lockPutterV1) putItem(item lockItem) error {
+	_, err := p.svc.PutItem(p.lock.putItemInputItem(item))
+	return err
+}
+
+func (p lockPutterV1) retryable(err error) bool {
+	return retryable(err)
+}
+
+func (p lockPutterV1) conditionFailed(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	return ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException
+}
+
+// LockResult describes the outcome of acquiring a lock. ExpiresAt is when the
+// acquired lock expires or, if the key was already locked, when the existing
+// lock expires. It is zero if the existing lock was removed before it could be
+// read.
+type LockResult struct {
+	Acquired  bool
+	Key       string
+	ExpiresAt time.Time
+}
+
+// AcquireDetailed is like Acquire but returns a LockResult, e.g. to decide
+// whether to requeue a message based on when the existing lock expires. If the
+// key is already locked the existing lock is read to get its expiry.
+func (lock *IdempotencyLock) AcquireDetailed(key string) (LockResult, error) {
+	result := LockResult{Key: key}
+	item := lock.lockItem(key, lock.TTL)
+
+	acquired, err := lock.acquire(item)
+	if err != nil {
+		return result, err
+	}
+
+	if acquired {
+		result.Acquired = true
+		result.ExpiresAt, err = epoch(item.expire)
+		return result, err
+	}
+
+	svc, err := lock.service()
+	if err != nil {
+		return result, err
+	}
+
+	output, err := svc.GetItem(lock.getItemInput(key))
+	if err != nil {
+		return result, errors.Wrapf(err, "failed get %v from %v", key, lock.Table)
+	}
+
+	if expire, ok := output.Item[lock.expireAttribute()]; ok {
+		result.ExpiresAt, err = epoch(aws.StringValue(expire.N))
+	}
+
+	return result, err
+}
+
+// getItemInput constructs the input for reading the expiry of the given id
+// from dynamodb
+func (lock *IdempotencyLock) getItemInput(id string) *dynamodb.GetItemInput {
+	return &dynamodb.GetItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			lock.keyAttribute(): {
+				S: aws.String(id),
+			},
+		},
+		TableName:                aws.String(lock.Table),
+		ConsistentRead:           aws.Bool(true),
+		ProjectionExpression:     aws.String("#expire"),
+		ExpressionAttributeNames: map[string]*string{"#expire": aws.String(lock.expireAttribute())},
+	}
+}
+
+// epoch parses the Epoch format string into a time
+func epoch(s string) (time.Time, error) {
+	t, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "invalid expire value '%v'", s)
+	}
+
+	return time.Unix(t, 0), nil
+}
+
+// Release removes the lock on the given key so that it can be acquired again
+// before its TTL expires, e.g. after a failed attempt to process it.
+func (lock *IdempotencyLock) Release(key string) error {
+	svc, err := lock.service()
+	if err != nil {
+		return err
+	}
+
+	_, err = svc.DeleteItem(lock.deleteItemInput(key))
+	if err != nil {
+		return errors.Wrapf(err, "failed delete %v from %v", key, lock.Table)
+	}
+
+	return nil
+}
+
+// Verify returns an error if the configured table doesn't exist or doesn't have
+// TTL enabled on the expire attribute, in which case expired locks are never
+// removed and the table grows forever. It is intended to be called once at cold
+// start.
+func (lock *IdempotencyLock) Verify() error {
+	svc, err := lock.service()
+	if err != nil {
+		return err
+	}
+
+	_, err = svc.DescribeTable(&dynamodb.DescribeTableInput{TableName: aws.String(lock.Table)})
+	if err != nil {
+		return errors.Wrapf(err, "failed describing table %v", lock.Table)
+	}
+
+	ttl, err := svc.DescribeTimeToLive(&dynamodb.DescribeTimeToLiveInput{TableName: aws.String(lock.Table)})
+	if err != nil {
+		return errors.Wrapf(err, "failed describing ttl of table %v", lock.Table)
+	}
+
+	var status, attribute string
+	if desc := ttl.TimeToLiveDescription; desc != nil {
+		status = aws.StringValue(desc.TimeToLiveStatus)
+		attribute = aws.StringValue(desc.AttributeName)
+	}
+
+	return lock.verifyTTL(status, attribute)
+}
+
+// SetClient sets the dynamodb client used for locking. This allows the client
+// to be created once at cold start and reused across lambda invocations rather
+// than creating a new session on every call.
+func (lock *IdempotencyLock) SetClient(svc dynamodbiface.DynamoDBAPI) {
+	lock.client = svc
+}
@@ -0,0 +1,251 @@
+package lambdautils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewIdempotencyLock(t *testing.T) {
+	l := NewIdempotencyLock("r1", "t1", 0, 0)
+
+	assert.Equal(t, "r1", l.Region)
+	assert.Equal(t, "t1", l.Table)
+	assert.Equal(t, int64(300), l.TTL)
+	assert.Equal(t, int64(500), l.RetryWait)
+	assert.Equal(t, "id", l.KeyAttribute)
+	assert.Equal(t, "expire", l.ExpireAttribute)
+	assert.Equal(t, 12, l.MaxAttempts)
+	assert.Equal(t, int64(10000), l.MaxRetryWait)
+}
+
+func TestNewIdempotencyLockFromJson(t *testing.T) {
+	l, err := NewIdempotencyLockFromJson(`{"region": "r1", "table": "t1", "ttl": 15, "key-attribute": "lock_key"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "r1", l.Region)
+	assert.Equal(t, "t1", l.Table)
+	assert.Equal(t, int64(15), l.TTL)
+	assert.Equal(t, "lock_key", l.KeyAttribute)
+	assert.Equal(t, "expire", l.ExpireAttribute)
+}
+
+func TestNewIdempotencyLockFromJson_error(t *testing.T) {
+	_, err := NewIdempotencyLockFromJson(`{`)
+	assert.Error(t, err)
+
+	_, err = NewIdempotencyLockFromJson(`{"table": "t1"}`)
+	assert.EqualError(t, err, "region is required")
+
+	_, err = NewIdempotencyLockFromJson(`{"region": "r1"}`)
+	assert.EqualError(t, err, "table is required")
+}
+
+func TestIdempotencyLock_expires(t *testing.T) {
+	l := &IdempotencyLock{LockConfig: LockConfig{TTL: 15}}
+	l.nowFunc = func() time.Time { return time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC) }
+
+	expected := "1257894015"
+	actual := l.expires()
+	assert.Equal(t, expected, actual)
+}
+
+func TestIdempotencyLock_current(t *testing.T) {
+	l := &IdempotencyLock{LockConfig: LockConfig{TTL: 15}}
+	l.nowFunc = func() time.Time { return time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC) }
+
+	expected := "1257894000"
+	actual := l.current()
+	assert.Equal(t, expected, actual)
+}
+
+func TestIdempotencyLock_putItemInput(t *testing.T) {
+	l := &IdempotencyLock{LockConfig: LockConfig{Region: "r1", Table: "t1", TTL: 900}}
+	l.nowFunc = func() time.Time { return time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC) }
+
+	input := l.putItemInput("1234")
+
+	assert.Equal(t, "t1", *input.TableName)
+	assert.Equal(t, "attribute_not_exists(#key) OR :cur > #expire", *input.ConditionExpression)
+	assert.Equal(t, "id", *input.ExpressionAttributeNames["#key"])
+	assert.Equal(t, "expire", *input.ExpressionAttributeNames["#expire"])
+	assert.Equal(t, "1257894000", *input.ExpressionAttributeValues[":cur"].N)
+	assert.Equal(t, "1234", *input.Item["id"].S)
+	assert.Equal(t, "1257894900", *input.Item["expire"].N)
+}
+
+func TestIdempotencyLock_putItemInput_attributes(t *testing.T) {
+	l := &IdempotencyLock{LockConfig: LockConfig{Region: "r1", Table: "t1", TTL: 900, KeyAttribute: "lock_key", ExpireAttribute: "ttl"}}
+	l.nowFunc = func() time.Time { return time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC) }
+
+	input := l.putItemInput("1234")
+
+	assert.Equal(t, "attribute_not_exists(#key) OR :cur > #expire", *input.ConditionExpression)
+	assert.Equal(t, "lock_key", *input.ExpressionAttributeNames["#key"])
+	assert.Equal(t, "ttl", *input.ExpressionAttributeNames["#expire"])
+	assert.Equal(t, "1234", *input.Item["lock_key"].S)
+	assert.Equal(t, "1257894900", *input.Item["ttl"].N)
+	assert.NotContains(t, input.Item, "id")
+	assert.NotContains(t, input.Item, "expire")
+}
+
+type flakyMockDynamoDBClient struct {
+	dynamodbiface.DynamoDBAPI
+	failures int
+	err      error
+	calls    int
+}
+
+func (m *flakyMockDynamoDBClient) PutItem(*dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+	m.calls++
+	if m.calls <= m.failures {
+		return nil, m.err
+	}
+	return nil, nil
+}
+
+func TestIdempotencyLock_backoff(t *testing.T) {
+	l := &IdempotencyLock{LockConfig: LockConfig{RetryWait: 500, MaxRetryWait: 3000}}
+
+	assert.Equal(t, 500*time.Millisecond, l.backoff(1))
+	assert.Equal(t, 1000*time.Millisecond, l.backoff(2))
+	assert.Equal(t, 2000*time.Millisecond, l.backoff(3))
+	assert.Equal(t, 3000*time.Millisecond, l.backoff(4))
+	assert.Equal(t, 3000*time.Millisecond, l.backoff(12))
+}
+
+func TestRetryable(t *testing.T) {
+	cases := []struct {
+		err      error
+		expected bool
+	}{
+		{awserr.New(dynamodb.ErrCodeProvisionedThroughputExceededException, "slow down", nil), true},
+		{awserr.New(dynamodb.ErrCodeRequestLimitExceeded, "slow down", nil), true},
+		{awserr.New("ThrottlingException", "slow down", nil), true},
+		{errors.New("read tcp: connection reset by peer"), true},
+		{awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "condition fail", nil), false},
+		{errors.New("test fail"), false},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, retryable(c.err), c.err.Error())
+	}
+}
+
+func TestIdempotencyLock_Acquire_retry(t *testing.T) {
+	mock := &flakyMockDynamoDBClient{
+		failures: 3,
+		err:      awserr.New(dynamodb.ErrCodeProvisionedThroughputExceededException, "slow down", nil),
+	}
+
+	var waits []time.Duration
+	l := &IdempotencyLock{LockConfig: LockConfig{Region: "r1", Table: "t1", TTL: 900, RetryWait: 100, MaxAttempts: 5, MaxRetryWait: 300}}
+	l.svcFunc = func(client.ConfigProvider) dynamodbiface.DynamoDBAPI { return mock }
+	l.sleepFunc = func(d time.Duration) { waits = append(waits, d) }
+
+	available, err := l.Acquire("1234")
+	assert.NoError(t, err)
+	assert.True(t, available)
+	assert.Equal(t, 4, mock.calls)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}, waits)
+}
+
+func TestIdempotencyLock_Acquire_retryExhausted(t *testing.T) {
+	mock := &flakyMockDynamoDBClient{
+		failures: 10,
+		err:      awserr.New("ThrottlingException", "slow down", nil),
+	}
+
+	l := &IdempotencyLock{LockConfig: LockConfig{Region: "r1", Table: "t1", TTL: 900, RetryWait: 100, MaxAttempts: 3}}
+	l.svcFunc = func(client.ConfigProvider) dynamodbiface.DynamoDBAPI { return mock }
+	l.sleepFunc = func(time.Duration) {}
+
+	available, err := l.Acquire("1234")
+	assert.Error(t, err)
+	assert.False(t, available)
+	assert.Equal(t, 3, mock.calls)
+}
+
+func TestIdempotencyLock_Acquire_noRetry(t *testing.T) {
+	mock := &flakyMockDynamoDBClient{failures: 10, err: errors.New("test fail")}
+
+	l := &IdempotencyLock{LockConfig: LockConfig{Region: "r1", Table: "t1", TTL: 900, RetryWait: 100}}
+	l.svcFunc = func(client.ConfigProvider) dynamodbiface.DynamoDBAPI { return mock }
+	l.sleepFunc = func(time.Duration) { t.Fatal("unexpected retry") }
+
+	_, err := l.Acquire("1234")
+	assert.Error(t, err)
+	assert.Equal(t, 1, mock.calls)
+}
+
+func TestIdempotencyLock_Acquire(t *testing.T) {
+	mock := &flakyMockDynamoDBClient{}
+	l := NewIdempotencyLock("r1", "t1", 900, 0)
+	l.SetClient(mock)
+
+	acquired, err := l.Acquire("idempotency-key-1")
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	assert.Equal(t, 1, mock.calls)
+}
+
+func TestIdempotencyLock_Acquire_locked(t *testing.T) {
+	l := NewIdempotencyLock("r1", "t1", 900, 0)
+	l.SetClient(&failedMockDynamoDBClient{})
+
+	acquired, err := l.Acquire("idempotency-key-1")
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+}
+
+func TestIdempotencyLock_Acquire_error(t *testing.T) {
+	l := NewIdempotencyLock("r1", "t1", 900, 0)
+	l.SetClient(&errorMockDynamoDBClient{})
+
+	_, err := l.Acquire("idempotency-key-1")
+	assert.EqualError(t, err, "failed put idempotency-key-1 to t1: test fail")
+}
+
+func TestIdempotencyLock_deleteItemInput(t *testing.T) {
+	l := &IdempotencyLock{LockConfig: LockConfig{Table: "t1", KeyAttribute: "lock_key"}}
+
+	input := l.deleteItemInput("1234")
+
+	assert.Equal(t, "t1", *input.TableName)
+	assert.Equal(t, "1234", *input.Key["lock_key"].S)
+	assert.Len(t, input.Key, 1)
+}
+
+type deleteMockDynamoDBClient struct {
+	dynamodbiface.DynamoDBAPI
+	err   error
+	input *dynamodb.DeleteItemInput
+}
+
+func (m *deleteMockDynamoDBClient) DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+	m.input = input
+	return nil, m.err
+}
+
+func TestIdempotencyLock_Release(t *testing.T) {
+	mock := &deleteMockDynamoDBClient{}
+	l := NewIdempotencyLock("r1", "t1", 900, 0)
+	l.svcFunc = func(client.ConfigProvider) dynamodbiface.DynamoDBAPI { return mock }
+
+	err := l.Release("idempotency-key-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "idempotency-key-1", *mock.input.Key["id"].S)
+}
+
+func TestIdempotencyLock_Release_error(t *testing.T) {
+	l := NewIdempotencyLock("r1", "t1", 900, 0)
+	l.SetClient(&deleteMockDynamoDBClient{err: errors.New("test fail")})
+
+	err := l.Release("idempotency-key-1")
+	assert.EqualError(t, err, "failed delete idempotency-key-1 from t1: test fail")
+}
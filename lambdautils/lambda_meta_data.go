@@ -2,17 +2,32 @@ package lambdautils
 
 import (
 	"context"
+	"math"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-lambda-go/lambdacontext"
 )
 
 // LambdaMetaData stored details about the current lambda context.
+//
+// AccountID and Region are parsed from the invoked function arn and are empty
+// when there is no lambda context.
+//
+// TraceID is the X-Ray root trace id parsed from the _X_AMZN_TRACE_ID
+// environment variable, which has the format
+// Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1.
+// It is empty when the variable is unset.
 type LambdaMetaData struct {
 	FunctionName    string
 	FunctionVersion string
 	LogGroupName    string
 	LogStreamName   string
 	MemoryLimitInMB int
+	AccountID       string
+	Region          string
+	TraceID         string
 	Context         *lambdacontext.LambdaContext
 }
 
@@ -24,8 +39,56 @@ func GetLambdaMetaData(ctx context.Context) LambdaMetaData {
 		LogGroupName:    lambdacontext.LogGroupName,
 		LogStreamName:   lambdacontext.LogStreamName,
 		MemoryLimitInMB: lambdacontext.MemoryLimitInMB,
+		TraceID:         parseTraceID(os.Getenv("_X_AMZN_TRACE_ID")),
 	}
 
 	lm.Context, _ = lambdacontext.FromContext(ctx)
+	if lm.Context != nil {
+		lm.Region, lm.AccountID = parseFunctionArn(lm.Context.InvokedFunctionArn)
+	}
+
 	return lm
 }
+
+// parseFunctionArn returns the region and account id from a lambda function
+// arn in the format arn:aws:lambda:REGION:ACCOUNT:function:NAME[:QUALIFIER]
+func parseFunctionArn(arn string) (string, string) {
+	parts := strings.Split(arn, ":")
+	if len(parts) < 5 || parts[0] != "arn" {
+		return "", ""
+	}
+
+	return parts[3], parts[4]
+}
+
+// parseTraceID returns the Root segment of an X-Ray trace header
+func parseTraceID(header string) string {
+	for _, segment := range strings.Split(header, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(segment), "=")
+		if ok && key == "Root" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// NoDeadline is returned by GetRemainingTime when the context has no deadline.
+const NoDeadline = time.Duration(math.MaxInt64)
+
+// GetRemainingTime returns the execution time remaining before the lambda
+// context deadline, or NoDeadline if the context has no deadline. Zero is
+// returned once the deadline has passed.
+func GetRemainingTime(ctx context.Context) time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return NoDeadline
+	}
+
+	remaining := time.Until(deadline)
+	if remaining < 0 {
+		return 0
+	}
+
+	return remaining
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-lambda-go/lambdacontext"
 	"github.com/stretchr/testify/assert"
@@ -67,5 +68,88 @@ func TestLambdaMetaData(t *testing.T) {
 		assert.Equal(t, "logGroupName-test", meta.LogGroupName)
 		assert.Equal(t, "logStreamName-test", meta.LogStreamName)
 		assert.Equal(t, c.expectedArn, meta.Context.InvokedFunctionArn)
+		assert.Equal(t, "us-east-1", meta.Region)
+		assert.Equal(t, "xxxxx", meta.AccountID)
 	}
 }
+
+func TestLambdaMetaData_noContext(t *testing.T) {
+	meta := GetLambdaMetaData(context.Background())
+
+	assert.Nil(t, meta.Context)
+	assert.Equal(t, "", meta.Region)
+	assert.Equal(t, "", meta.AccountID)
+}
+
+func TestLambdaMetaData_traceID(t *testing.T) {
+	t.Setenv("_X_AMZN_TRACE_ID", "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1")
+
+	meta := GetLambdaMetaData(context.Background())
+	assert.Equal(t, "1-5759e988-bd862e3fe1be46a994272793", meta.TraceID)
+}
+
+func TestLambdaMetaData_traceIDUnset(t *testing.T) {
+	t.Setenv("_X_AMZN_TRACE_ID", "")
+
+	meta := GetLambdaMetaData(context.Background())
+	assert.Equal(t, "", meta.TraceID)
+}
+
+func TestParseTraceID(t *testing.T) {
+	cases := []struct {
+		header   string
+		expected string
+	}{
+		{"Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1", "1-5759e988-bd862e3fe1be46a994272793"},
+		{"Parent=53995c3f42cd8ad8;Root=1-5759e988-bd862e3fe1be46a994272793", "1-5759e988-bd862e3fe1be46a994272793"},
+		{"Root=1-5759e988-bd862e3fe1be46a994272793", "1-5759e988-bd862e3fe1be46a994272793"},
+		{"Parent=53995c3f42cd8ad8;Sampled=1", ""},
+		{"", ""},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, parseTraceID(c.header), c.header)
+	}
+}
+
+func TestParseFunctionArn(t *testing.T) {
+	cases := []struct {
+		arn             string
+		expectedRegion  string
+		expectedAccount string
+	}{
+		{"arn:aws:lambda:us-west-2:123456789012:function:fname", "us-west-2", "123456789012"},
+		{"arn:aws:lambda:eu-west-1:123456789012:function:fname:PRODUCTION", "eu-west-1", "123456789012"},
+		{"arn:aws:lambda:eu-west-1:123456789012:function:fname:$LATEST", "eu-west-1", "123456789012"},
+		{"arn:aws-us-gov:lambda:us-gov-west-1:123456789012:function:fname:7", "us-gov-west-1", "123456789012"},
+		{"", "", ""},
+		{"not-an-arn", "", ""},
+		{"arn:aws:lambda", "", ""},
+	}
+
+	for _, c := range cases {
+		region, account := parseFunctionArn(c.arn)
+		assert.Equal(t, c.expectedRegion, region, c.arn)
+		assert.Equal(t, c.expectedAccount, account, c.arn)
+	}
+}
+
+func TestGetRemainingTime(t *testing.T) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Minute))
+	defer cancel()
+
+	remaining := GetRemainingTime(ctx)
+	assert.True(t, remaining > 59*time.Second, remaining.String())
+	assert.True(t, remaining <= time.Minute, remaining.String())
+}
+
+func TestGetRemainingTime_expired(t *testing.T) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Minute))
+	defer cancel()
+
+	assert.Equal(t, time.Duration(0), GetRemainingTime(ctx))
+}
+
+func TestGetRemainingTime_noDeadline(t *testing.T) {
+	assert.Equal(t, NoDeadline, GetRemainingTime(context.Background()))
+}
@@ -0,0 +1,238 @@
+package lambdautils
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// LockConfig is the configuration shared by the dynamodb locks. A key is locked
+// once acquired and the lock expires after the TTL (seconds) has expired.
+//
+// RetryWait (milliseconds) is the base wait used for exponential retry backoff
+// on throttled or reset requests. Each retry doubles the wait, capped at
+// MaxRetryWait (milliseconds), for up to MaxAttempts attempts.
+//
+// KeyAttribute and ExpireAttribute are the names of the dynamodb table's
+// partition key and expiry attributes, defaulting to 'id' and 'expire'.
+//
+// Region is used to create the aws-sdk-go v1 client when none is set, it is
+// unused by SNSLockV2 which is given a client.
+type LockConfig struct {
+	Region          string `json:"region"`
+	Table           string `json:"table"`
+	TTL             int64  `json:"ttl"`
+	RetryWait       int64  `json:"retry-wait"`
+	KeyAttribute    string `json:"key-attribute"`
+	ExpireAttribute string `json:"expire-attribute"`
+	MaxAttempts     int    `json:"max-attempts"`
+	MaxRetryWait    int64  `json:"max-retry-wait"`
+
+	nowFunc   func() time.Time
+	sleepFunc func(time.Duration)
+}
+
+const (
+	defaultTTL             = 300
+	defaultRetryWait       = 500
+	defaultKeyAttribute    = "id"
+	defaultExpireAttribute = "expire"
+	defaultMaxAttempts     = 12
+	defaultMaxRetryWait    = 10000
+
+	// lockCondition fails the put when the id exists and has not yet expired
+	lockCondition = "attribute_not_exists(#key) OR :cur > #expire"
+)
+
+// validate returns an error if required configuration is missing
+func (config *LockConfig) validate() error {
+	if config.Region == "" {
+		return errors.New("region is required")
+	}
+
+	return config.validateTable()
+}
+
+// validateTable returns an error if the table is missing
+func (config *LockConfig) validateTable() error {
+	if config.Table == "" {
+		return errors.New("table is required")
+	}
+
+	return nil
+}
+
+// setDefaults fills in any unset optional configuration
+func (config *LockConfig) setDefaults() {
+	if config.TTL == 0 {
+		config.TTL = defaultTTL
+	}
+
+	if config.RetryWait == 0 {
+		config.RetryWait = defaultRetryWait
+	}
+
+	if config.KeyAttribute == "" {
+		config.KeyAttribute = defaultKeyAttribute
+	}
+
+	if config.ExpireAttribute == "" {
+		config.ExpireAttribute = defaultExpireAttribute
+	}
+
+	if config.MaxAttempts == 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+
+	if config.MaxRetryWait == 0 {
+		config.MaxRetryWait = defaultMaxRetryWait
+	}
+}
+
+// now is used internally to assist stubs on time.Now() for testing
+func (config *LockConfig) now() time.Time {
+	if config.nowFunc != nil {
+		return config.nowFunc()
+	}
+
+	return time.Now()
+}
+
+// sleep is used internally to assist stubs on time.Sleep() for testing
+func (config *LockConfig) sleep(d time.Duration) {
+	if config.sleepFunc != nil {
+		config.sleepFunc(d)
+		return
+	}
+
+	time.Sleep(d)
+}
+
+// keyAttribute returns the name of the partition key attribute
+func (config *LockConfig) keyAttribute() string {
+	if config.KeyAttribute != "" {
+		return config.KeyAttribute
+	}
+
+	return defaultKeyAttribute
+}
+
+// expireAttribute returns the name of the expiry attribute
+func (config *LockConfig) expireAttribute() string {
+	if config.ExpireAttribute != "" {
+		return config.ExpireAttribute
+	}
+
+	return defaultExpireAttribute
+}
+
+// maxAttempts returns the maximum number of put attempts
+func (config *LockConfig) maxAttempts() int {
+	if config.MaxAttempts > 0 {
+		return config.MaxAttempts
+	}
+
+	return defaultMaxAttempts
+}
+
+// backoff returns the wait before the given retry attempt (1 based), doubling
+// RetryWait on each attempt and capping it at MaxRetryWait.
+func (config *LockConfig) backoff(attempt int) time.Duration {
+	max := config.MaxRetryWait
+	if max <= 0 {
+		max = defaultMaxRetryWait
+	}
+
+	wait := config.RetryWait
+	for i := 1; i < attempt && wait < max; i++ {
+		wait *= 2
+	}
+
+	if wait > max {
+		wait = max
+	}
+
+	return time.Duration(wait) * time.Millisecond
+}
+
+// expires returns the current time + ttl in Epoch format as a string
+func (config *LockConfig) expires() string {
+	return config.expiresIn(config.TTL)
+}
+
+// expiresIn returns the current time + the given ttl (seconds) in Epoch format
+// as a string
+func (config *LockConfig) expiresIn(ttl int64) string {
+	d := time.Duration(ttl) * time.Second
+	t := config.now().Add(d).Unix()
+	return strconv.FormatInt(t, 10)
+}
+
+// current returns the current time in Epoch format as a string
+func (config *LockConfig) current() string {
+	return strconv.FormatInt(config.now().Unix(), 10)
+}
+
+// lockItem is the lock record conditionally put into dynamodb, independent of
+// the aws sdk version.
+type lockItem struct {
+	key     string
+	expire  string
+	current string
+}
+
+// lockItem returns the lock record for the given key expiring after the given
+// ttl (seconds)
+func (config *LockConfig) lockItem(key string, ttl int64) lockItem {
+	return lockItem{key: key, expire: config.expiresIn(ttl), current: config.current()}
+}
+
+// lockPutter puts lock items into dynamodb using a specific aws sdk version
+type lockPutter interface {
+	// putItem conditionally puts the item using lockCondition
+	putItem(item lockItem) error
+	// retryable returns true if the put error is transient and worth retrying
+	retryable(err error) bool
+	// conditionFailed returns true if the put failed because the key is locked
+	conditionFailed(err error) bool
+}
+
+// put conditionally puts the lock item using the putter, retrying transient
+// errors, and returns false if the key is already locked.
+func (config *LockConfig) put(putter lockPutter, item lockItem) (bool, error) {
+	var err error
+	for attempt := 1; attempt <= config.maxAttempts(); attempt++ {
+		err = putter.putItem(item)
+		if err == nil || !putter.retryable(err) || attempt == config.maxAttempts() {
+			break
+		}
+
+		config.sleep(config.backoff(attempt))
+	}
+
+	if err == nil {
+		return true, nil
+	}
+
+	if putter.conditionFailed(err) {
+		return false, nil
+	}
+
+	return false, errors.Wrapf(err, "failed put %v to %v", item.key, config.Table)
+}
+
+// verifyTTL returns an error unless the ttl status is enabled on the expire
+// attribute
+func (config *LockConfig) verifyTTL(status string, attribute string) error {
+	if status != "ENABLED" {
+		return fmt.Errorf("ttl is not enabled on table %v, status: %v", config.Table, status)
+	}
+
+	if attribute != config.expireAttribute() {
+		return fmt.Errorf("ttl of table %v is enabled on attribute %v, expected: %v", config.Table, attribute, config.expireAttribute())
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package lambdautils
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/pkg/errors"
+)
+
+// snsHasher derives lock keys from sns messages. It is shared by SNSLock and
+// SNSLockV2 which only differ in the dynamodb client used for locking.
+type snsHasher struct {
+	hashFunc     func(string) (string, error)
+	scopeTopic   bool
+	scopeSubject bool
+}
+
+// material returns the content of the sns entity that is hashed, the message
+// optionally prefixed by the topic arn and subject, see WithTopicScope and
+// WithSubjectScope.
+func (h snsHasher) material(entity events.SNSEntity) string {
+	parts := []string{}
+
+	if h.scopeTopic {
+		parts = append(parts, entity.TopicArn)
+	}
+
+	if h.scopeSubject {
+		parts = append(parts, entity.Subject)
+	}
+
+	return strings.Join(append(parts, entity.Message), "\x00")
+}
+
+// hash returns the sha256 of the given sns message
+func (h snsHasher) hash(message string) (string, error) {
+	// If a hash function is provided, use it
+	if h.hashFunc != nil {
+		return h.hashFunc(message)
+	}
+
+	sum := sha256.Sum256([]byte(message))
+	return fmt.Sprintf("%x", sum), nil
+}
+
+// messageHash returns the sha256 of the first message embedded in the sns event
+func (h snsHasher) messageHash(snsEvent events.SNSEvent) (string, error) {
+	return h.hash(h.material(snsEvent.Records[0].SNS))
+}
+
+// key returns the lock key for the single message embedded in the sns event
+func (h snsHasher) key(snsEvent events.SNSEvent) (string, error) {
+	if len(snsEvent.Records) != 1 {
+		return "", fmt.Errorf("expected only 1 SNS event, received: %v", len(snsEvent.Records))
+	}
+
+	return h.messageHash(snsEvent)
+}
+
+// available hashes the single message in the snsEvent and acquires it using
+// availableById
+func (h snsHasher) available(snsEvent events.SNSEvent, availableById func(string) (bool, error)) (bool, error) {
+	if len(snsEvent.Records) != 1 {
+		return false, fmt.Errorf("expected only 1 SNS event, received: %v", len(snsEvent.Records))
+	}
+
+	id, err := h.messageHash(snsEvent)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to hash message")
+	}
+	return availableById(id)
+}
+
+// availableDetailed hashes the single message in the snsEvent and acquires it
+// using acquireDetailed
+func (h snsHasher) availableDetailed(snsEvent events.SNSEvent, acquireDetailed func(string) (LockResult, error)) (LockResult, error) {
+	if len(snsEvent.Records) != 1 {
+		return LockResult{}, fmt.Errorf("expected only 1 SNS event, received: %v", len(snsEvent.Records))
+	}
+
+	id, err := h.messageHash(snsEvent)
+	if err != nil {
+		return LockResult{}, errors.Wrap(err, "failed to hash message")
+	}
+
+	return acquireDetailed(id)
+}
+
+// availableBatch checks each record in the snsEvent using availableById
+func (h snsHasher) availableBatch(snsEvent events.SNSEvent, availableById func(string) (bool, error)) (map[int]bool, error) {
+	available := make(map[int]bool, len(snsEvent.Records))
+
+	for i, record := range snsEvent.Records {
+		id, err := h.hash(h.material(record.SNS))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to hash message %d", i)
+		}
+
+		ok, err := availableById(id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed checking message %d", i)
+		}
+
+		available[i] = ok
+	}
+
+	return available, nil
+}
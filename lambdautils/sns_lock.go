@@ -1,60 +1,81 @@
 package lambdautils
 
 import (
-	"crypto/sha256"
 	"encoding/json"
-	"fmt"
-	"strconv"
-	"strings"
-	"time"
 
 	"github.com/aws/aws-lambda-go/events"
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/client"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/dynamodb"
-	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
-	"github.com/pkg/errors"
 )
 
 // SNSLock manages locking of sns messages using dynamodb. The SNS messages are
 // locked using the hash of their message contents and the lock expires after
 // the TTL (seconds) has expired.
 //
-// RetryWait (milliseconds) is used to manage retry backoff times.
+// SNSLock is an IdempotencyLock keyed by the message hash, see LockConfig for
+// the configuration options. By default the hash is the hex encoded sha256
+// of the raw SNS message, this can be changed using WithHashFunc. The topic arn
+// and subject can be included in the hashed material using WithTopicScope and
+// WithSubjectScope.
 type SNSLock struct {
-	Region    string `json:"region"`
-	Table     string `json:"table"`
-	TTL       int64  `json:"ttl"`
-	RetryWait int64  `json:"retry-wait"`
-
-	nowFunc  func() time.Time
-	svcFunc  func(client.ConfigProvider) dynamodbiface.DynamoDBAPI
-	hashFunc func(string) (string, error)
+	IdempotencyLock
+
+	hasher snsHasher
 }
 
-// NewSNSLock returns a new sns lock instance to manage dynamodb locking
-func NewSNSLock(region string, table string, ttl int64, retry int64) *SNSLock {
-	lock := new(SNSLock)
-	lock.Region = region
-	lock.Table = table
-	lock.TTL = ttl
-	lock.RetryWait = retry
+// SNSLockOption configures an SNSLock or SNSLockV2 on construction
+type SNSLockOption func(config *LockConfig, hasher *snsHasher)
+
+// WithHashFunc sets the function used to hash the raw SNS message, including
+// any scoping material, into the lock key
+func WithHashFunc(f func(string) (string, error)) SNSLockOption {
+	return func(config *LockConfig, hasher *snsHasher) {
+		hasher.hashFunc = f
+	}
+}
+
+// WithAttributeNames sets the names of the dynamodb table's partition key and
+// expiry attributes. An empty name keeps the configured name.
+func WithAttributeNames(key string, expire string) SNSLockOption {
+	return func(config *LockConfig, hasher *snsHasher) {
+		if key != "" {
+			config.KeyAttribute = key
+		}
+
+		if expire != "" {
+			config.ExpireAttribute = expire
+		}
+	}
+}
+
+// WithTopicScope includes the TopicArn in the hashed material so that identical
+// messages delivered by different topics don't share a lock
+func WithTopicScope() SNSLockOption {
+	return func(config *LockConfig, hasher *snsHasher) {
+		hasher.scopeTopic = true
+	}
+}
 
-	if lock.TTL == 0 {
-		lock.TTL = 300
+// WithSubjectScope includes the Subject in the hashed material so that
+// identical messages with different subjects don't share a lock
+func WithSubjectScope() SNSLockOption {
+	return func(config *LockConfig, hasher *snsHasher) {
+		hasher.scopeSubject = true
 	}
+}
+
+// NewSNSLock returns a new sns lock instance to manage dynamodb locking
+func NewSNSLock(region string, table string, ttl int64, retry int64, opts ...SNSLockOption) *SNSLock {
+	lock := new(SNSLock)
+	lock.IdempotencyLock = *NewIdempotencyLock(region, table, ttl, retry)
 
-	if lock.RetryWait == 0 {
-		lock.RetryWait = 500
+	for _, opt := range opts {
+		opt(&lock.LockConfig, &lock.hasher)
 	}
 
 	return lock
 }
 
 // NewSNSLockFromJson returns a new sns lock instance to manage dynamodb locking
-func NewSNSLockFromJson(s string) (*SNSLock, error) {
+func NewSNSLockFromJson(s string, opts ...SNSLockOption) (*SNSLock, error) {
 	lock := new(SNSLock)
 
 	err := json.Unmarshal([]byte(s), lock)
@@ -62,91 +83,29 @@ func NewSNSLockFromJson(s string) (*SNSLock, error) {
 		return nil, err
 	}
 
-	if lock.Region == "" {
-		return nil, errors.New("region is required")
+	err = lock.validate()
+	if err != nil {
+		return nil, err
 	}
 
-	if lock.Table == "" {
-		return nil, errors.New("table is required")
-	}
+	lock.setDefaults()
 
-	if lock.TTL == 0 {
-		lock.TTL = 300
-	}
-
-	if lock.RetryWait == 0 {
-		lock.RetryWait = 500
+	for _, opt := range opts {
+		opt(&lock.LockConfig, &lock.hasher)
 	}
 
 	return lock, nil
 }
 
-// now is used internally to assist stubs on time.Now() for testing
-func (lock *SNSLock) now() time.Time {
-	if lock.nowFunc != nil {
-		return lock.nowFunc()
-	}
-
-	return time.Now()
-}
-
-// svc is used internally to assist stubs on dynamodb for testing
-func (lock *SNSLock) svc(p client.ConfigProvider) dynamodbiface.DynamoDBAPI {
-	if lock.svcFunc != nil {
-		return lock.svcFunc(p)
-	}
-
-	return dynamodb.New(p)
-}
-
 // messageHash returns the sha256 of the message embedded in the sns event
 func (lock *SNSLock) messageHash(snsEvent events.SNSEvent) (string, error) {
-	message := snsEvent.Records[0].SNS.Message
-
-	// If a hash function is provided, use it
-	if lock.hashFunc != nil {
-		return lock.hashFunc(message)
-	}
-
-	sum := sha256.Sum256([]byte(message))
-	return fmt.Sprintf("%x", sum), nil
-}
-
-// expires returns the current time + ttl in Epoch format as a string
-func (lock *SNSLock) expires() string {
-	d := time.Duration(lock.TTL) * time.Second
-	t := lock.now().Add(d).Unix()
-	return strconv.FormatInt(t, 10)
+	return lock.hasher.messageHash(snsEvent)
 }
 
-// current returns the current time in Epoch format as a string
-func (lock *SNSLock) current() string {
-	return strconv.FormatInt(lock.now().Unix(), 10)
-}
-
-// putItemInput constructs the input for the given id insertion into dynamodb.
-// It applies a conditional expression that causes failures when the id has
-// already been added but not yet expired.
-func (lock *SNSLock) putItemInput(id string) *dynamodb.PutItemInput {
-	condition := "attribute_not_exists(id) OR :cur > expire"
-
-	return &dynamodb.PutItemInput{
-		Item: map[string]*dynamodb.AttributeValue{
-			"id": {
-				S: aws.String(id),
-			},
-			"expire": {
-				N: aws.String(lock.expires()),
-			},
-		},
-		TableName:           aws.String(lock.Table),
-		ConditionExpression: aws.String(condition),
-		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
-			":cur": {
-				N: aws.String(lock.current()),
-			},
-		},
-	}
+// MessageHash returns the lock key for the single message embedded in the sns
+// event, e.g. for logging which message was already locked.
+func (lock *SNSLock) MessageHash(snsEvent events.SNSEvent) (string, error) {
+	return lock.hasher.key(snsEvent)
 }
 
 // AvailableById returns true if the given id is available for use (not locked)
@@ -155,40 +114,14 @@ func (lock *SNSLock) putItemInput(id string) *dynamodb.PutItemInput {
 // Locked is defined as the record being in the configured dynamodb table and
 // not expires.
 func (lock *SNSLock) AvailableById(id string) (bool, error) {
-	s, err := session.NewSession(&aws.Config{
-		Region: aws.String(lock.Region),
-	})
-
-	if err != nil {
-		return false, errors.Wrap(err, "failed getting session")
-	}
-
-	svc := lock.svc(s)
-	input := lock.putItemInput(id)
-
-	for attempts := 1; attempts <= 12; attempts++ {
-		_, err = svc.PutItem(input)
-		if err == nil {
-			break
-		}
-		errString := err.Error()
-		if strings.Contains(errString, "connection reset by peer") {
-			time.Sleep(time.Duration(lock.TTL) * time.Millisecond)
-			continue // retry
-		}
-		break
-	}
-
-	if err == nil {
-		return true, nil
-	}
-
-	aerr, ok := err.(awserr.Error)
-	if ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
-		return false, nil
-	}
+	return lock.Acquire(id)
+}
 
-	return false, errors.Wrapf(err, "failed put %v to %v", id, lock.Table)
+// AvailableByIdWithTTL is like AvailableById but the lock expires after the
+// given ttl (seconds) instead of the configured TTL, e.g. so that dedup of a
+// heavy job lasts longer than that of a cheap one.
+func (lock *SNSLock) AvailableByIdWithTTL(id string, ttl int64) (bool, error) {
+	return lock.AcquireWithTTL(id, ttl)
 }
 
 // Available returns true if the snsEvent is available for use (not locked) and
@@ -197,18 +130,25 @@ func (lock *SNSLock) AvailableById(id string) (bool, error) {
 // Locked is defined as the record being in the configured dynamodb table and
 // not expires.
 func (lock *SNSLock) Available(snsEvent events.SNSEvent) (bool, error) {
-	if len(snsEvent.Records) != 1 {
-		return false, fmt.Errorf("expected only 1 SNS event, received: %v", len(snsEvent.Records))
-	}
+	return lock.hasher.available(snsEvent, lock.AvailableById)
+}
 
-	id, err := lock.messageHash(snsEvent)
-	if err != nil {
-		return false, errors.Wrap(err, "failed to hash message")
-	}
-	return lock.AvailableById(id)
+// AvailableDetailed is like Available but returns a LockResult that carries the
+// lock key and when the lock expires, see IdempotencyLock.AcquireDetailed.
+func (lock *SNSLock) AvailableDetailed(snsEvent events.SNSEvent) (LockResult, error) {
+	return lock.hasher.availableDetailed(snsEvent, lock.AcquireDetailed)
+}
+
+// AvailableBatch checks each record in the snsEvent independently and returns
+// the availability of each keyed by its index in snsEvent.Records, so that the
+// available records can be processed and the locked ones skipped.
+//
+// Use Available when exactly one record is expected.
+func (lock *SNSLock) AvailableBatch(snsEvent events.SNSEvent) (map[int]bool, error) {
+	return lock.hasher.availableBatch(snsEvent, lock.AvailableById)
 }
 
 // SetHashFunc sets the hash function to use for message hashing
 func (lock *SNSLock) SetHashFunc(f func(string) (string, error)) {
-	lock.hashFunc = f
+	lock.hasher.hashFunc = f
 }
@@ -37,6 +37,10 @@ func TestNewSNSLock(t *testing.T) {
 		assert.Equal(t, "t", l.Table)
 		assert.Equal(t, c.expectedTTL, l.TTL)
 		assert.Equal(t, c.expectedRetryWait, l.RetryWait)
+		assert.Equal(t, "id", l.KeyAttribute)
+		assert.Equal(t, "expire", l.ExpireAttribute)
+		assert.Equal(t, 12, l.MaxAttempts)
+		assert.Equal(t, int64(10000), l.MaxRetryWait)
 	}
 }
 
@@ -47,11 +51,14 @@ func TestNewSNSLockFromJson(t *testing.T) {
 		expectedTable     string
 		expectedTTL       int64
 		expectedRetryWait int64
+		expectedKey       string
+		expectedExpire    string
 	}{
-		{`{"region": "r1", "table": "t1", "ttl": 15}`, "r1", "t1", 15, 500},
-		{`{"region": "r2", "table": "t2", "ttl": 30}`, "r2", "t2", 30, 500},
-		{`{"region": "r3", "table": "t3"}`, "r3", "t3", 300, 500},
-		{`{"region": "r3", "table": "t3", "retry-wait": 250}`, "r3", "t3", 300, 250},
+		{`{"region": "r1", "table": "t1", "ttl": 15}`, "r1", "t1", 15, 500, "id", "expire"},
+		{`{"region": "r2", "table": "t2", "ttl": 30}`, "r2", "t2", 30, 500, "id", "expire"},
+		{`{"region": "r3", "table": "t3"}`, "r3", "t3", 300, 500, "id", "expire"},
+		{`{"region": "r3", "table": "t3", "retry-wait": 250}`, "r3", "t3", 300, 250, "id", "expire"},
+		{`{"region": "r4", "table": "t4", "key-attribute": "lock_key", "expire-attribute": "ttl"}`, "r4", "t4", 300, 500, "lock_key", "ttl"},
 	}
 
 	for _, c := range cases {
@@ -62,6 +69,10 @@ func TestNewSNSLockFromJson(t *testing.T) {
 		assert.Equal(t, c.expectedTable, l.Table)
 		assert.Equal(t, c.expectedTTL, l.TTL)
 		assert.Equal(t, c.expectedRetryWait, l.RetryWait)
+		assert.Equal(t, c.expectedKey, l.KeyAttribute)
+		assert.Equal(t, c.expectedExpire, l.ExpireAttribute)
+		assert.Equal(t, 12, l.MaxAttempts)
+		assert.Equal(t, int64(10000), l.MaxRetryWait)
 	}
 }
 
@@ -104,6 +115,73 @@ func TestSNSLock_messageHash(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestSNSLock_messageHash_topicScope(t *testing.T) {
+	event := func(topic string, subject string) events.SNSEvent {
+		return events.SNSEvent{
+			Records: []events.SNSEventRecord{
+				{SNS: events.SNSEntity{TopicArn: topic, Subject: subject, Message: "same body"}},
+			},
+		}
+	}
+
+	topicA := "arn:aws:sns:us-east-1:123456789012:a"
+	topicB := "arn:aws:sns:us-east-1:123456789012:b"
+
+	unscoped := NewSNSLock("r1", "t1", 900, 0)
+
+	a, err := unscoped.messageHash(event(topicA, ""))
+	assert.NoError(t, err)
+	b, err := unscoped.messageHash(event(topicB, ""))
+	assert.NoError(t, err)
+	assert.Equal(t, a, b)
+	assert.Equal(t, fmt.Sprintf("%x", sha256.Sum256([]byte("same body"))), a)
+
+	scoped := NewSNSLock("r1", "t1", 900, 0, WithTopicScope())
+
+	a, err = scoped.messageHash(event(topicA, "s1"))
+	assert.NoError(t, err)
+	b, err = scoped.messageHash(event(topicB, "s1"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, a, b)
+
+	c, err := scoped.messageHash(event(topicA, "s2"))
+	assert.NoError(t, err)
+	assert.Equal(t, a, c)
+
+	subject := NewSNSLock("r1", "t1", 900, 0, WithTopicScope(), WithSubjectScope())
+
+	a, err = subject.messageHash(event(topicA, "s1"))
+	assert.NoError(t, err)
+	c, err = subject.messageHash(event(topicA, "s2"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, a, c)
+}
+
+func TestSNSLock_AvailableBatch_topicScope(t *testing.T) {
+	snsEvent := events.SNSEvent{
+		Records: []events.SNSEventRecord{
+			{SNS: events.SNSEntity{TopicArn: "arn:aws:sns:us-east-1:123456789012:a", Message: "same body"}},
+			{SNS: events.SNSEntity{TopicArn: "arn:aws:sns:us-east-1:123456789012:b", Message: "same body"}},
+		},
+	}
+
+	var keys []string
+
+	l := NewSNSLock("r1", "t1", 900, 0, WithTopicScope(), WithHashFunc(func(material string) (string, error) {
+		keys = append(keys, material)
+		return material, nil
+	}))
+	l.SetClient(&successMockDynamoDBClient{})
+
+	available, err := l.AvailableBatch(snsEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]bool{0: true, 1: true}, available)
+	assert.Equal(t, []string{
+		"arn:aws:sns:us-east-1:123456789012:a\x00same body",
+		"arn:aws:sns:us-east-1:123456789012:b\x00same body",
+	}, keys)
+}
+
 func TestSNSLock_messageHash_json(t *testing.T) {
 	b, err := os.ReadFile("testdata/valid_sns_json_event.json")
 	assert.NoError(t, err)
@@ -164,8 +242,91 @@ func TestSNSLock_messageHash_s3(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestNewSNSLock_options(t *testing.T) {
+	hash := func(message string) (string, error) {
+		return "custom-" + message, nil
+	}
+
+	l := NewSNSLock("r1", "t1", 0, 0, WithHashFunc(hash), WithAttributeNames("lock_key", "ttl"))
+
+	assert.Equal(t, "lock_key", l.KeyAttribute)
+	assert.Equal(t, "ttl", l.ExpireAttribute)
+
+	snsEvent := events.SNSEvent{
+		Records: []events.SNSEventRecord{
+			{SNS: events.SNSEntity{Message: "hello"}},
+		},
+	}
+
+	actual, err := l.messageHash(snsEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, "custom-hello", actual)
+}
+
+func TestNewSNSLockFromJson_options(t *testing.T) {
+	hash := func(message string) (string, error) {
+		return "custom-" + message, nil
+	}
+
+	l, err := NewSNSLockFromJson(`{"region": "r1", "table": "t1"}`, WithHashFunc(hash), WithAttributeNames("lock_key", ""))
+	assert.NoError(t, err)
+	assert.Equal(t, "lock_key", l.KeyAttribute)
+	assert.Equal(t, "expire", l.ExpireAttribute)
+
+	snsEvent := events.SNSEvent{
+		Records: []events.SNSEventRecord{
+			{SNS: events.SNSEntity{Message: "hello"}},
+		},
+	}
+
+	actual, err := l.messageHash(snsEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, "custom-hello", actual)
+}
+
+func TestNewSNSLockFromJson_optionsAfterDefaults(t *testing.T) {
+	fromJson, err := NewSNSLockFromJson(`{"region": "r1", "table": "t1", "key-attribute": "pk"}`, WithAttributeNames("lock_key", "ttl"))
+	assert.NoError(t, err)
+
+	l := NewSNSLock("r1", "t1", 0, 0, WithAttributeNames("lock_key", "ttl"))
+
+	assert.Equal(t, l.IdempotencyLock, fromJson.IdempotencyLock)
+	assert.Equal(t, "lock_key", fromJson.KeyAttribute)
+	assert.Equal(t, "ttl", fromJson.ExpireAttribute)
+}
+
+func TestSNSLock_MessageHash(t *testing.T) {
+	b, err := os.ReadFile("testdata/valid_sns_string_event.json")
+	assert.NoError(t, err)
+
+	snsEventRecord := &events.SNSEventRecord{}
+	assert.NoError(t, json.Unmarshal(b, snsEventRecord))
+
+	snsEvent := events.SNSEvent{
+		Records: []events.SNSEventRecord{
+			*snsEventRecord,
+		},
+	}
+
+	l := NewSNSLock("r1", "t1", 900, 0)
+
+	expected, err := l.messageHash(snsEvent)
+	assert.NoError(t, err)
+
+	actual, err := l.MessageHash(snsEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestSNSLock_MessageHash_errorRecords(t *testing.T) {
+	l := NewSNSLock("r1", "t1", 900, 0)
+
+	_, err := l.MessageHash(events.SNSEvent{})
+	assert.Error(t, err)
+}
+
 func TestSNSLock_expires(t *testing.T) {
-	l := &SNSLock{TTL: 15}
+	l := NewSNSLock("r1", "t1", 15, 0)
 	l.nowFunc = func() time.Time { return time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC) }
 
 	expected := "1257894015"
@@ -174,7 +335,7 @@ func TestSNSLock_expires(t *testing.T) {
 }
 
 func TestSNSLock_current(t *testing.T) {
-	l := &SNSLock{TTL: 15}
+	l := NewSNSLock("r1", "t1", 15, 0)
 	l.nowFunc = func() time.Time { return time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC) }
 
 	expected := "1257894000"
@@ -183,18 +344,35 @@ func TestSNSLock_current(t *testing.T) {
 }
 
 func TestSNSLock_putItemInput(t *testing.T) {
-	l := &SNSLock{Region: "r1", Table: "t1", TTL: 900}
+	l := NewSNSLock("r1", "t1", 900, 0)
 	l.nowFunc = func() time.Time { return time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC) }
 
 	input := l.putItemInput("1234")
 
 	assert.Equal(t, "t1", *input.TableName)
-	assert.Equal(t, "attribute_not_exists(id) OR :cur > expire", *input.ConditionExpression)
+	assert.Equal(t, "attribute_not_exists(#key) OR :cur > #expire", *input.ConditionExpression)
+	assert.Equal(t, "id", *input.ExpressionAttributeNames["#key"])
+	assert.Equal(t, "expire", *input.ExpressionAttributeNames["#expire"])
 	assert.Equal(t, "1257894000", *input.ExpressionAttributeValues[":cur"].N)
 	assert.Equal(t, "1234", *input.Item["id"].S)
 	assert.Equal(t, "1257894900", *input.Item["expire"].N)
 }
 
+func TestSNSLock_putItemInput_attributes(t *testing.T) {
+	l := NewSNSLock("r1", "t1", 900, 0, WithAttributeNames("lock_key", "ttl"))
+	l.nowFunc = func() time.Time { return time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC) }
+
+	input := l.putItemInput("1234")
+
+	assert.Equal(t, "attribute_not_exists(#key) OR :cur > #expire", *input.ConditionExpression)
+	assert.Equal(t, "lock_key", *input.ExpressionAttributeNames["#key"])
+	assert.Equal(t, "ttl", *input.ExpressionAttributeNames["#expire"])
+	assert.Equal(t, "1234", *input.Item["lock_key"].S)
+	assert.Equal(t, "1257894900", *input.Item["ttl"].N)
+	assert.NotContains(t, input.Item, "id")
+	assert.NotContains(t, input.Item, "expire")
+}
+
 type successMockDynamoDBClient struct {
 	dynamodbiface.DynamoDBAPI
 }
@@ -219,8 +397,28 @@ func (m *errorMockDynamoDBClient) PutItem(*dynamodb.PutItemInput) (*dynamodb.Put
 	return nil, errors.New("test fail")
 }
 
+func TestSNSLock_AvailableById_retry(t *testing.T) {
+	mock := &flakyMockDynamoDBClient{
+		failures: 3,
+		err:      awserr.New(dynamodb.ErrCodeProvisionedThroughputExceededException, "slow down", nil),
+	}
+
+	var waits []time.Duration
+	l := NewSNSLock("r1", "t1", 900, 100)
+	l.MaxAttempts = 5
+	l.MaxRetryWait = 300
+	l.svcFunc = func(client.ConfigProvider) dynamodbiface.DynamoDBAPI { return mock }
+	l.sleepFunc = func(d time.Duration) { waits = append(waits, d) }
+
+	available, err := l.AvailableById("1234")
+	assert.NoError(t, err)
+	assert.True(t, available)
+	assert.Equal(t, 4, mock.calls)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}, waits)
+}
+
 func TestSNSLock_AvailableById(t *testing.T) {
-	l := &SNSLock{Region: "r1", Table: "t1", TTL: 900}
+	l := NewSNSLock("r1", "t1", 900, 0)
 	l.svcFunc = func(client.ConfigProvider) dynamodbiface.DynamoDBAPI { return &successMockDynamoDBClient{} }
 
 	available, err := l.AvailableById("1234")
@@ -229,7 +427,7 @@ func TestSNSLock_AvailableById(t *testing.T) {
 }
 
 func TestSNSLock_AvailableById_nope(t *testing.T) {
-	l := &SNSLock{Region: "r1", Table: "t1", TTL: 900}
+	l := NewSNSLock("r1", "t1", 900, 0)
 	l.svcFunc = func(client.ConfigProvider) dynamodbiface.DynamoDBAPI { return &failedMockDynamoDBClient{} }
 
 	available, err := l.AvailableById("1234")
@@ -238,13 +436,82 @@ func TestSNSLock_AvailableById_nope(t *testing.T) {
 }
 
 func TestSNSLock_AvailableById_error(t *testing.T) {
-	l := &SNSLock{Region: "r1", Table: "t1", TTL: 900}
+	l := NewSNSLock("r1", "t1", 900, 0)
 	l.svcFunc = func(client.ConfigProvider) dynamodbiface.DynamoDBAPI { return &errorMockDynamoDBClient{} }
 
 	_, err := l.AvailableById("1234")
 	assert.Error(t, err)
 }
 
+func TestSNSLock_AvailableById_client(t *testing.T) {
+	mock := &flakyMockDynamoDBClient{}
+
+	l := NewSNSLock("r1", "t1", 900, 0)
+	l.SetClient(mock)
+	l.svcFunc = func(client.ConfigProvider) dynamodbiface.DynamoDBAPI {
+		t.Fatal("unexpected session")
+		return nil
+	}
+
+	available, err := l.AvailableById("1234")
+	assert.NoError(t, err)
+	assert.True(t, available)
+
+	available, err = l.AvailableById("5678")
+	assert.NoError(t, err)
+	assert.True(t, available)
+	assert.Equal(t, 2, mock.calls)
+}
+
+func TestSNSLock_AvailableById_clientNope(t *testing.T) {
+	l := NewSNSLock("r1", "t1", 900, 0)
+	l.SetClient(&failedMockDynamoDBClient{})
+
+	available, err := l.AvailableById("1234")
+	assert.NoError(t, err)
+	assert.False(t, available)
+}
+
+type recordingMockDynamoDBClient struct {
+	dynamodbiface.DynamoDBAPI
+	input *dynamodb.PutItemInput
+}
+
+func (m *recordingMockDynamoDBClient) PutItem(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+	m.input = input
+	return nil, nil
+}
+
+func TestSNSLock_AvailableByIdWithTTL(t *testing.T) {
+	mock := &recordingMockDynamoDBClient{}
+
+	l := NewSNSLock("r1", "t1", 900, 0)
+	l.SetClient(mock)
+	l.nowFunc = func() time.Time { return time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC) }
+
+	available, err := l.AvailableByIdWithTTL("1234", 3600)
+	assert.NoError(t, err)
+	assert.True(t, available)
+	assert.Equal(t, "1234", *mock.input.Item["id"].S)
+	assert.Equal(t, "1257897600", *mock.input.Item["expire"].N)
+
+	available, err = l.AvailableById("1234")
+	assert.NoError(t, err)
+	assert.True(t, available)
+	assert.Equal(t, "1257894900", *mock.input.Item["expire"].N)
+}
+
+func TestSNSLock_AvailableByIdWithTTL_errorTTL(t *testing.T) {
+	mock := &recordingMockDynamoDBClient{}
+
+	l := NewSNSLock("r1", "t1", 900, 0)
+	l.SetClient(mock)
+
+	_, err := l.AvailableByIdWithTTL("1234", 0)
+	assert.EqualError(t, err, "ttl must be positive, received: 0")
+	assert.Nil(t, mock.input)
+}
+
 func TestSNSLock_Available(t *testing.T) {
 	b, err := os.ReadFile("testdata/valid_sns_string_event.json")
 	assert.NoError(t, err)
@@ -258,7 +525,7 @@ func TestSNSLock_Available(t *testing.T) {
 		},
 	}
 
-	l := &SNSLock{Region: "r1", Table: "t1", TTL: 900}
+	l := NewSNSLock("r1", "t1", 900, 0)
 	l.svcFunc = func(client.ConfigProvider) dynamodbiface.DynamoDBAPI { return &successMockDynamoDBClient{} }
 
 	available, err := l.Available(snsEvent)
@@ -280,9 +547,207 @@ func TestSNSLock_Available_errorRecords(t *testing.T) {
 		},
 	}
 
-	l := &SNSLock{Region: "r1", Table: "t1", TTL: 900}
+	l := NewSNSLock("r1", "t1", 900, 0)
 	l.svcFunc = func(client.ConfigProvider) dynamodbiface.DynamoDBAPI { return &successMockDynamoDBClient{} }
 
 	_, err = l.Available(snsEvent)
 	assert.Error(t, err)
 }
+
+type lockedMockDynamoDBClient struct {
+	dynamodbiface.DynamoDBAPI
+	locked map[string]bool
+}
+
+func (m *lockedMockDynamoDBClient) PutItem(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+	if m.locked[*input.Item["id"].S] {
+		return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "condition fail", nil)
+	}
+	return nil, nil
+}
+
+func TestSNSLock_AvailableBatch(t *testing.T) {
+	snsEvent := events.SNSEvent{
+		Records: []events.SNSEventRecord{
+			{SNS: events.SNSEntity{Message: "first"}},
+			{SNS: events.SNSEntity{Message: "second"}},
+		},
+	}
+
+	hash := func(message string) (string, error) { return message, nil }
+
+	l := NewSNSLock("r1", "t1", 900, 0, WithHashFunc(hash))
+	l.SetClient(&lockedMockDynamoDBClient{locked: map[string]bool{"second": true}})
+
+	available, err := l.AvailableBatch(snsEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]bool{0: true, 1: false}, available)
+}
+
+func TestSNSLock_AvailableBatch_empty(t *testing.T) {
+	l := NewSNSLock("r1", "t1", 900, 0)
+	l.SetClient(&successMockDynamoDBClient{})
+
+	available, err := l.AvailableBatch(events.SNSEvent{})
+	assert.NoError(t, err)
+	assert.Empty(t, available)
+}
+
+func TestSNSLock_AvailableBatch_errorHash(t *testing.T) {
+	snsEvent := events.SNSEvent{
+		Records: []events.SNSEventRecord{
+			{SNS: events.SNSEntity{Message: "first"}},
+		},
+	}
+
+	hash := func(message string) (string, error) { return "", errors.New("test fail") }
+
+	l := NewSNSLock("r1", "t1", 900, 0, WithHashFunc(hash))
+	l.SetClient(&successMockDynamoDBClient{})
+
+	_, err := l.AvailableBatch(snsEvent)
+	assert.EqualError(t, err, "failed to hash message 0: test fail")
+}
+
+func TestSNSLock_AvailableBatch_error(t *testing.T) {
+	snsEvent := events.SNSEvent{
+		Records: []events.SNSEventRecord{
+			{SNS: events.SNSEntity{Message: "first"}},
+		},
+	}
+
+	l := NewSNSLock("r1", "t1", 900, 0)
+	l.SetClient(&errorMockDynamoDBClient{})
+
+	_, err := l.AvailableBatch(snsEvent)
+	assert.Error(t, err)
+}
+
+type describeMockDynamoDBClient struct {
+	dynamodbiface.DynamoDBAPI
+	tableErr  error
+	status    string
+	attribute string
+}
+
+func (m *describeMockDynamoDBClient) DescribeTable(*dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error) {
+	if m.tableErr != nil {
+		return nil, m.tableErr
+	}
+	return &dynamodb.DescribeTableOutput{}, nil
+}
+
+func (m *describeMockDynamoDBClient) DescribeTimeToLive(*dynamodb.DescribeTimeToLiveInput) (*dynamodb.DescribeTimeToLiveOutput, error) {
+	return &dynamodb.DescribeTimeToLiveOutput{
+		TimeToLiveDescription: &dynamodb.TimeToLiveDescription{
+			TimeToLiveStatus: &m.status,
+			AttributeName:    &m.attribute,
+		},
+	}, nil
+}
+
+func TestSNSLock_Verify(t *testing.T) {
+	l := NewSNSLock("r1", "t1", 900, 0)
+	l.SetClient(&describeMockDynamoDBClient{status: "ENABLED", attribute: "expire"})
+
+	assert.NoError(t, l.Verify())
+}
+
+func TestSNSLock_Verify_disabled(t *testing.T) {
+	l := NewSNSLock("r1", "t1", 900, 0)
+	l.SetClient(&describeMockDynamoDBClient{status: "DISABLED"})
+
+	assert.EqualError(t, l.Verify(), "ttl is not enabled on table t1, status: DISABLED")
+}
+
+func TestSNSLock_Verify_attribute(t *testing.T) {
+	l := NewSNSLock("r1", "t1", 900, 0, WithAttributeNames("pk", "ttl"))
+	l.SetClient(&describeMockDynamoDBClient{status: "ENABLED", attribute: "expire"})
+
+	assert.EqualError(t, l.Verify(), "ttl of table t1 is enabled on attribute expire, expected: ttl")
+}
+
+func TestSNSLock_Verify_missingTable(t *testing.T) {
+	l := NewSNSLock("r1", "t1", 900, 0)
+	l.SetClient(&describeMockDynamoDBClient{
+		tableErr: awserr.New(dynamodb.ErrCodeResourceNotFoundException, "table not found", nil),
+	})
+
+	err := l.Verify()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed describing table t1")
+}
+
+type expiringMockDynamoDBClient struct {
+	failedMockDynamoDBClient
+	expire *string
+	input  *dynamodb.GetItemInput
+}
+
+func (m *expiringMockDynamoDBClient) GetItem(input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+	m.input = input
+	if m.expire == nil {
+		return &dynamodb.GetItemOutput{}, nil
+	}
+	return &dynamodb.GetItemOutput{
+		Item: map[string]*dynamodb.AttributeValue{"expire": {N: m.expire}},
+	}, nil
+}
+
+func TestSNSLock_AvailableDetailed(t *testing.T) {
+	snsEvent := events.SNSEvent{
+		Records: []events.SNSEventRecord{{SNS: events.SNSEntity{Message: "yolo"}}},
+	}
+
+	l := NewSNSLock("r1", "t1", 900, 0)
+	l.SetClient(&successMockDynamoDBClient{})
+	l.nowFunc = func() time.Time { return time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC) }
+
+	result, err := l.AvailableDetailed(snsEvent)
+	assert.NoError(t, err)
+	assert.True(t, result.Acquired)
+	assert.Equal(t, fmt.Sprintf("%x", sha256.Sum256([]byte("yolo"))), result.Key)
+	assert.Equal(t, time.Unix(1257894900, 0), result.ExpiresAt)
+}
+
+func TestSNSLock_AvailableDetailed_locked(t *testing.T) {
+	snsEvent := events.SNSEvent{
+		Records: []events.SNSEventRecord{{SNS: events.SNSEntity{Message: "yolo"}}},
+	}
+
+	expire := "1257894600"
+	mock := &expiringMockDynamoDBClient{expire: &expire}
+
+	l := NewSNSLock("r1", "t1", 900, 0)
+	l.SetClient(mock)
+
+	result, err := l.AvailableDetailed(snsEvent)
+	assert.NoError(t, err)
+	assert.False(t, result.Acquired)
+	assert.Equal(t, fmt.Sprintf("%x", sha256.Sum256([]byte("yolo"))), result.Key)
+	assert.Equal(t, time.Unix(1257894600, 0), result.ExpiresAt)
+	assert.Equal(t, result.Key, *mock.input.Key["id"].S)
+	assert.True(t, *mock.input.ConsistentRead)
+}
+
+func TestSNSLock_AvailableDetailed_lockedGone(t *testing.T) {
+	snsEvent := events.SNSEvent{
+		Records: []events.SNSEventRecord{{SNS: events.SNSEntity{Message: "yolo"}}},
+	}
+
+	l := NewSNSLock("r1", "t1", 900, 0)
+	l.SetClient(&expiringMockDynamoDBClient{})
+
+	result, err := l.AvailableDetailed(snsEvent)
+	assert.NoError(t, err)
+	assert.False(t, result.Acquired)
+	assert.True(t, result.ExpiresAt.IsZero())
+}
+
+func TestSNSLock_AvailableDetailed_errorRecords(t *testing.T) {
+	l := NewSNSLock("r1", "t1", 900, 0)
+	l.SetClient(&successMockDynamoDBClient{})
+
+	_, err := l.AvailableDetailed(events.SNSEvent{})
+	assert.EqualError(t, err, "expected only 1 SNS event, received: 0")
+}
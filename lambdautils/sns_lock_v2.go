@@ -0,0 +1,316 @@
+package lambdautils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/pkg/errors"
+)
+
+// DynamoDBPutItemAPI is the subset of the aws-sdk-go-v2 dynamodb client used by
+// SNSLockV2. It is satisfied by *dynamodb.Client.
+type DynamoDBPutItemAPI interface {
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+}
+
+// DynamoDBDeleteItemAPI is the subset of the aws-sdk-go-v2 dynamodb client used
+// by SNSLockV2.Release. It is satisfied by *dynamodb.Client.
+type DynamoDBDeleteItemAPI interface {
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+}
+
+// DynamoDBGetItemAPI is the subset of the aws-sdk-go-v2 dynamodb client used by
+// SNSLockV2.AcquireDetailed. It is satisfied by *dynamodb.Client.
+type DynamoDBGetItemAPI interface {
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+}
+
+// DynamoDBDescribeAPI is the subset of the aws-sdk-go-v2 dynamodb client used
+// by SNSLockV2.Verify. It is satisfied by *dynamodb.Client.
+type DynamoDBDescribeAPI interface {
+	DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+	DescribeTimeToLive(ctx context.Context, params *dynamodb.DescribeTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error)
+}
+
+// SNSLockV2 manages locking of sns messages using the aws-sdk-go-v2 dynamodb
+// client. It shares its configuration, hashing and expiry behaviour with
+// SNSLock and keeps the same conditional put semantics, see LockConfig for the
+// configuration options.
+type SNSLockV2 struct {
+	LockConfig
+
+	client DynamoDBPutItemAPI
+	hasher snsHasher
+}
+
+// NewSNSLockV2 returns a new sns lock instance to manage dynamodb locking with
+// the given aws-sdk-go-v2 client.
+func NewSNSLockV2(client DynamoDBPutItemAPI, table string, ttl int64, retry int64, opts ...SNSLockOption) *SNSLockV2 {
+	lock := &SNSLockV2{client: client}
+	lock.Table = table
+	lock.TTL = ttl
+	lock.RetryWait = retry
+	lock.setDefaults()
+
+	for _, opt := range opts {
+		opt(&lock.LockConfig, &lock.hasher)
+	}
+
+	return lock
+}
+
+// NewSNSLockV2FromJson returns a new sns lock instance to manage dynamodb
+// locking with the given aws-sdk-go-v2 client. The region is not required as
+// the client is already configured.
+func NewSNSLockV2FromJson(client DynamoDBPutItemAPI, s string, opts ...SNSLockOption) (*SNSLockV2, error) {
+	lock := &SNSLockV2{client: client}
+
+	err := json.Unmarshal([]byte(s), lock)
+	if err != nil {
+		return nil, err
+	}
+
+	err = lock.validateTable()
+	if err != nil {
+		return nil, err
+	}
+
+	lock.setDefaults()
+
+	for _, opt := range opts {
+		opt(&lock.LockConfig, &lock.hasher)
+	}
+
+	return lock, nil
+}
+
+// putItemInputV2 constructs the aws-sdk-go-v2 input for the given id insertion
+// into dynamodb. See IdempotencyLock.putItemInput.
+func (lock *SNSLockV2) putItemInputV2(id string) *dynamodb.PutItemInput {
+	return lock.putItemInputV2Item(lock.lockItem(id, lock.TTL))
+}
+
+// putItemInputV2Item constructs the aws-sdk-go-v2 input for the conditional put
+// of the lock item into dynamodb.
+func (lock *SNSLockV2) putItemInputV2Item(item lockItem) *dynamodb.PutItemInput {
+	return &dynamodb.PutItemInput{
+		Item: map[string]types.AttributeValue{
+			lock.keyAttribute():    &types.AttributeValueMemberS{Value: item.key},
+			lock.expireAttribute(): &types.AttributeValueMemberN{Value: item.expire},
+		},
+		TableName:           aws.String(lock.Table),
+		ConditionExpression: aws.String(lockCondition),
+		ExpressionAttributeNames: map[string]string{
+			"#key":    lock.keyAttribute(),
+			"#expire": lock.expireAttribute(),
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":cur": &types.AttributeValueMemberN{Value: item.current},
+		},
+	}
+}
+
+// retryableV2 returns true if the aws-sdk-go-v2 put error is transient and
+// worth retrying
+func retryableV2(err error) bool {
+	var aerr smithy.APIError
+	if errors.As(err, &aerr) {
+		switch aerr.ErrorCode() {
+		case "ProvisionedThroughputExceededException",
+			"RequestLimitExceeded",
+			"ThrottlingException",
+			"Throttling":
+			return true
+		}
+	}
+
+	return strings.Contains(err.Error(), "connection reset by peer")
+}
+
+// AvailableById returns true if the given id is available for use (not locked)
+// and it returns false if it is locked.
+//
+// Locked is defined as the record being in the configured dynamodb table and
+// not expires.
+func (lock *SNSLockV2) AvailableById(id string) (bool, error) {
+	return lock.AvailableByIdWithTTL(id, lock.TTL)
+}
+
+// AvailableByIdWithTTL is like AvailableById but the lock expires after the
+// given ttl (seconds) instead of the configured TTL.
+func (lock *SNSLockV2) AvailableByIdWithTTL(id string, ttl int64) (bool, error) {
+	if lock.client == nil {
+		return false, errors.New("dynamodb client is required")
+	}
+
+	if ttl <= 0 {
+		return false, fmt.Errorf("ttl must be positive, received: %v", ttl)
+	}
+
+	return lock.put(lock, lock.lockItem(id, ttl))
+}
+
+// putItem conditionally puts the lock item using the aws-sdk-go-v2 client, see
+// lockPutter.
+func (lock *SNSLockV2) putItem(item lockItem) error {
+	_, err := lock.client.PutItem(context.Background(), lock.putItemInputV2Item(item))
+	return err
+}
+
+// retryable returns true if the aws-sdk-go-v2 put error is transient and worth
+// retrying, see lockPutter.
+func (lock *SNSLockV2) retryable(err error) bool {
+	return retryableV2(err)
+}
+
+// conditionFailed returns true if the aws-sdk-go-v2 put failed because the key
+// is already locked, see lockPutter.
+func (lock *SNSLockV2) conditionFailed(err error) bool {
+	var cerr *types.ConditionalCheckFailedException
+	return errors.As(err, &cerr)
+}
+
+// Acquire returns true if the given key was available and is now locked, and
+// it returns false if it is already locked.
+func (lock *SNSLockV2) Acquire(key string) (bool, error) {
+	return lock.AvailableById(key)
+}
+
+// AcquireWithTTL is like Acquire but the lock expires after the given ttl
+// (seconds) instead of the configured TTL.
+func (lock *SNSLockV2) AcquireWithTTL(key string, ttl int64) (bool, error) {
+	return lock.AvailableByIdWithTTL(key, ttl)
+}
+
+// AcquireDetailed is like Acquire but returns a LockResult, see
+// IdempotencyLock.AcquireDetailed. The client must also implement
+// DynamoDBGetItemAPI to read the expiry of an existing lock.
+func (lock *SNSLockV2) AcquireDetailed(key string) (LockResult, error) {
+	result := LockResult{Key: key}
+
+	if lock.client == nil {
+		return result, errors.New("dynamodb client is required")
+	}
+
+	item := lock.lockItem(key, lock.TTL)
+
+	acquired, err := lock.put(lock, item)
+	if err != nil {
+		return result, err
+	}
+
+	if acquired {
+		result.Acquired = true
+		result.ExpiresAt, err = epoch(item.expire)
+		return result, err
+	}
+
+	svc, ok := lock.client.(DynamoDBGetItemAPI)
+	if !ok {
+		return result, errors.New("dynamodb client does not support GetItem")
+	}
+
+	output, err := svc.GetItem(context.Background(), &dynamodb.GetItemInput{
+		Key: map[string]types.AttributeValue{
+			lock.keyAttribute(): &types.AttributeValueMemberS{Value: key},
+		},
+		TableName:                aws.String(lock.Table),
+		ConsistentRead:           aws.Bool(true),
+		ProjectionExpression:     aws.String("#expire"),
+		ExpressionAttributeNames: map[string]string{"#expire": lock.expireAttribute()},
+	})
+	if err != nil {
+		return result, errors.Wrapf(err, "failed get %v from %v", key, lock.Table)
+	}
+
+	if expire, ok := output.Item[lock.expireAttribute()].(*types.AttributeValueMemberN); ok {
+		result.ExpiresAt, err = epoch(expire.Value)
+	}
+
+	return result, err
+}
+
+// Release removes the lock on the given key so that it can be acquired again
+// before its TTL expires. The client must also implement DynamoDBDeleteItemAPI.
+func (lock *SNSLockV2) Release(key string) error {
+	svc, ok := lock.client.(DynamoDBDeleteItemAPI)
+	if !ok {
+		return errors.New("dynamodb client does not support DeleteItem")
+	}
+
+	input := &dynamodb.DeleteItemInput{
+		Key: map[string]types.AttributeValue{
+			lock.keyAttribute(): &types.AttributeValueMemberS{Value: key},
+		},
+		TableName: aws.String(lock.Table),
+	}
+
+	_, err := svc.DeleteItem(context.Background(), input)
+	if err != nil {
+		return errors.Wrapf(err, "failed delete %v from %v", key, lock.Table)
+	}
+
+	return nil
+}
+
+// Verify returns an error if the configured table doesn't exist or doesn't have
+// TTL enabled on the expire attribute, see IdempotencyLock.Verify. The client
+// must also implement DynamoDBDescribeAPI.
+func (lock *SNSLockV2) Verify() error {
+	svc, ok := lock.client.(DynamoDBDescribeAPI)
+	if !ok {
+		return errors.New("dynamodb client does not support DescribeTable and DescribeTimeToLive")
+	}
+
+	_, err := svc.DescribeTable(context.Background(), &dynamodb.DescribeTableInput{TableName: aws.String(lock.Table)})
+	if err != nil {
+		return errors.Wrapf(err, "failed describing table %v", lock.Table)
+	}
+
+	ttl, err := svc.DescribeTimeToLive(context.Background(), &dynamodb.DescribeTimeToLiveInput{TableName: aws.String(lock.Table)})
+	if err != nil {
+		return errors.Wrapf(err, "failed describing ttl of table %v", lock.Table)
+	}
+
+	var status, attribute string
+	if desc := ttl.TimeToLiveDescription; desc != nil {
+		status = string(desc.TimeToLiveStatus)
+		attribute = aws.ToString(desc.AttributeName)
+	}
+
+	return lock.verifyTTL(status, attribute)
+}
+
+// Available returns true if the snsEvent is available for use (not locked) and
+// it returns false if it is locked.
+//
+// Locked is defined as the record being in the configured dynamodb table and
+// not expires.
+func (lock *SNSLockV2) Available(snsEvent events.SNSEvent) (bool, error) {
+	return lock.hasher.available(snsEvent, lock.AvailableById)
+}
+
+// AvailableDetailed is like Available but returns a LockResult that carries the
+// lock key and when the lock expires, see AcquireDetailed.
+func (lock *SNSLockV2) AvailableDetailed(snsEvent events.SNSEvent) (LockResult, error) {
+	return lock.hasher.availableDetailed(snsEvent, lock.AcquireDetailed)
+}
+
+// AvailableBatch checks each record in the snsEvent independently and returns
+// the availability of each keyed by its index in snsEvent.Records.
+func (lock *SNSLockV2) AvailableBatch(snsEvent events.SNSEvent) (map[int]bool, error) {
+	return lock.hasher.availableBatch(snsEvent, lock.AvailableById)
+}
+
+// MessageHash returns the lock key for the single message embedded in the sns
+// event, see SNSLock.MessageHash.
+func (lock *SNSLockV2) MessageHash(snsEvent events.SNSEvent) (string, error) {
+	return lock.hasher.key(snsEvent)
+}
@@ -0,0 +1,334 @@
+package lambdautils
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDynamoDBPutItemAPI struct {
+	failures int
+	err      error
+	calls    int
+	input    *dynamodb.PutItemInput
+}
+
+func (m *mockDynamoDBPutItemAPI) PutItem(ctx context.Context, input *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	m.calls++
+	m.input = input
+	if m.calls <= m.failures {
+		return nil, m.err
+	}
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func TestNewSNSLockV2(t *testing.T) {
+	l := NewSNSLockV2(&mockDynamoDBPutItemAPI{}, "t1", 0, 0)
+
+	assert.Equal(t, "t1", l.Table)
+	assert.Equal(t, int64(300), l.TTL)
+	assert.Equal(t, int64(500), l.RetryWait)
+	assert.Equal(t, "id", l.KeyAttribute)
+	assert.Equal(t, "expire", l.ExpireAttribute)
+}
+
+func TestNewSNSLockV2FromJson(t *testing.T) {
+	l, err := NewSNSLockV2FromJson(&mockDynamoDBPutItemAPI{}, `{"table": "t1", "ttl": 15, "key-attribute": "pk"}`, WithAttributeNames("", "ttl"))
+	assert.NoError(t, err)
+	assert.Equal(t, "t1", l.Table)
+	assert.Equal(t, int64(15), l.TTL)
+	assert.Equal(t, int64(500), l.RetryWait)
+	assert.Equal(t, "pk", l.KeyAttribute)
+	assert.Equal(t, "ttl", l.ExpireAttribute)
+	assert.Equal(t, 12, l.MaxAttempts)
+}
+
+func TestNewSNSLockV2FromJson_error(t *testing.T) {
+	_, err := NewSNSLockV2FromJson(&mockDynamoDBPutItemAPI{}, `{`)
+	assert.Error(t, err)
+
+	_, err = NewSNSLockV2FromJson(&mockDynamoDBPutItemAPI{}, `{"region": "r1"}`)
+	assert.EqualError(t, err, "table is required")
+}
+
+func TestNewSNSLockV2_options(t *testing.T) {
+	hash := func(message string) (string, error) { return message, nil }
+
+	mock := &mockDynamoDBPutItemAPI{}
+	l := NewSNSLockV2(mock, "t1", 900, 0, WithHashFunc(hash), WithTopicScope(), WithAttributeNames("lock_key", "ttl"))
+
+	assert.Equal(t, "lock_key", l.KeyAttribute)
+	assert.Equal(t, "ttl", l.ExpireAttribute)
+
+	snsEvent := events.SNSEvent{
+		Records: []events.SNSEventRecord{
+			{SNS: events.SNSEntity{TopicArn: "arn:aws:sns:us-east-1:123456789012:a", Message: "hello"}},
+		},
+	}
+
+	available, err := l.Available(snsEvent)
+	assert.NoError(t, err)
+	assert.True(t, available)
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "arn:aws:sns:us-east-1:123456789012:a\x00hello"}, mock.input.Item["lock_key"])
+}
+
+func TestSNSLockV2_methods(t *testing.T) {
+	l := reflect.TypeOf(&SNSLockV2{})
+
+	for _, name := range []string{"SetClient", "SetHashFunc"} {
+		_, ok := l.MethodByName(name)
+		assert.False(t, ok, name)
+	}
+}
+
+func TestSNSLockV2_putItemInputV2(t *testing.T) {
+	l := NewSNSLockV2(&mockDynamoDBPutItemAPI{}, "t1", 900, 0)
+	l.nowFunc = func() time.Time { return time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC) }
+
+	input := l.putItemInputV2("1234")
+
+	assert.Equal(t, "t1", *input.TableName)
+	assert.Equal(t, "attribute_not_exists(#key) OR :cur > #expire", *input.ConditionExpression)
+	assert.Equal(t, map[string]string{"#key": "id", "#expire": "expire"}, input.ExpressionAttributeNames)
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "1257894000"}, input.ExpressionAttributeValues[":cur"])
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "1234"}, input.Item["id"])
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "1257894900"}, input.Item["expire"])
+}
+
+func TestSNSLockV2_AvailableById(t *testing.T) {
+	mock := &mockDynamoDBPutItemAPI{}
+	l := NewSNSLockV2(mock, "t1", 900, 0)
+
+	available, err := l.AvailableById("1234")
+	assert.NoError(t, err)
+	assert.True(t, available)
+	assert.Equal(t, 1, mock.calls)
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "1234"}, mock.input.Item["id"])
+}
+
+func TestSNSLockV2_AvailableByIdWithTTL(t *testing.T) {
+	mock := &mockDynamoDBPutItemAPI{}
+	l := NewSNSLockV2(mock, "t1", 900, 0)
+	l.nowFunc = func() time.Time { return time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC) }
+
+	available, err := l.AvailableByIdWithTTL("1234", 3600)
+	assert.NoError(t, err)
+	assert.True(t, available)
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "1257897600"}, mock.input.Item["expire"])
+
+	_, err = l.AvailableByIdWithTTL("1234", -1)
+	assert.EqualError(t, err, "ttl must be positive, received: -1")
+	assert.Equal(t, 1, mock.calls)
+}
+
+func TestSNSLockV2_AvailableById_nope(t *testing.T) {
+	mock := &mockDynamoDBPutItemAPI{failures: 1, err: &types.ConditionalCheckFailedException{Message: aws.String("condition fail")}}
+	l := NewSNSLockV2(mock, "t1", 900, 0)
+
+	available, err := l.AvailableById("1234")
+	assert.NoError(t, err)
+	assert.False(t, available)
+}
+
+func TestSNSLockV2_AvailableById_retry(t *testing.T) {
+	mock := &mockDynamoDBPutItemAPI{failures: 2, err: &smithy.GenericAPIError{Code: "ThrottlingException"}}
+	l := NewSNSLockV2(mock, "t1", 900, 0)
+	l.sleepFunc = func(time.Duration) {}
+
+	available, err := l.AvailableById("1234")
+	assert.NoError(t, err)
+	assert.True(t, available)
+	assert.Equal(t, 3, mock.calls)
+}
+
+func TestSNSLockV2_AvailableById_error(t *testing.T) {
+	mock := &mockDynamoDBPutItemAPI{failures: 1, err: errors.New("test fail")}
+	l := NewSNSLockV2(mock, "t1", 900, 0)
+
+	_, err := l.AvailableById("1234")
+	assert.Error(t, err)
+	assert.Equal(t, 1, mock.calls)
+}
+
+func TestSNSLockV2_AvailableById_errorClient(t *testing.T) {
+	l := NewSNSLockV2(nil, "t1", 900, 0)
+
+	_, err := l.AvailableById("1234")
+	assert.Error(t, err)
+}
+
+func TestSNSLockV2_Available(t *testing.T) {
+	b, err := os.ReadFile("testdata/valid_sns_string_event.json")
+	assert.NoError(t, err)
+
+	snsEventRecord := &events.SNSEventRecord{}
+	assert.NoError(t, json.Unmarshal(b, snsEventRecord))
+
+	snsEvent := events.SNSEvent{
+		Records: []events.SNSEventRecord{
+			*snsEventRecord,
+		},
+	}
+
+	mock := &mockDynamoDBPutItemAPI{}
+	l := NewSNSLockV2(mock, "t1", 900, 0)
+
+	available, err := l.Available(snsEvent)
+	assert.NoError(t, err)
+	assert.True(t, available)
+
+	hash, err := l.MessageHash(snsEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, &types.AttributeValueMemberS{Value: hash}, mock.input.Item["id"])
+}
+
+func TestSNSLockV2_Available_errorRecords(t *testing.T) {
+	l := NewSNSLockV2(&mockDynamoDBPutItemAPI{}, "t1", 900, 0)
+
+	_, err := l.Available(events.SNSEvent{})
+	assert.Error(t, err)
+}
+
+type deleteMockDynamoDBAPI struct {
+	mockDynamoDBPutItemAPI
+	input *dynamodb.DeleteItemInput
+}
+
+func (m *deleteMockDynamoDBAPI) DeleteItem(ctx context.Context, input *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	m.input = input
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func TestSNSLockV2_Acquire(t *testing.T) {
+	mock := &mockDynamoDBPutItemAPI{}
+	l := NewSNSLockV2(mock, "t1", 900, 0)
+
+	acquired, err := l.Acquire("1234")
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	assert.Equal(t, 1, mock.calls)
+}
+
+func TestSNSLockV2_Release(t *testing.T) {
+	mock := &deleteMockDynamoDBAPI{}
+	l := NewSNSLockV2(mock, "t1", 900, 0)
+
+	assert.NoError(t, l.Release("1234"))
+	assert.Equal(t, "t1", *mock.input.TableName)
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "1234"}, mock.input.Key["id"])
+}
+
+func TestSNSLockV2_Release_errorClient(t *testing.T) {
+	l := NewSNSLockV2(&mockDynamoDBPutItemAPI{}, "t1", 900, 0)
+
+	assert.Error(t, l.Release("1234"))
+}
+
+func TestSNSLockV2_AvailableBatch(t *testing.T) {
+	snsEvent := events.SNSEvent{
+		Records: []events.SNSEventRecord{
+			{SNS: events.SNSEntity{Message: "first"}},
+			{SNS: events.SNSEntity{Message: "second"}},
+		},
+	}
+
+	mock := &mockDynamoDBPutItemAPI{failures: 1, err: &types.ConditionalCheckFailedException{}}
+	l := NewSNSLockV2(mock, "t1", 900, 0)
+
+	available, err := l.AvailableBatch(snsEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]bool{0: false, 1: true}, available)
+	assert.Equal(t, 2, mock.calls)
+}
+
+type describeMockDynamoDBAPI struct {
+	mockDynamoDBPutItemAPI
+	status types.TimeToLiveStatus
+}
+
+func (m *describeMockDynamoDBAPI) DescribeTable(ctx context.Context, input *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	return &dynamodb.DescribeTableOutput{}, nil
+}
+
+func (m *describeMockDynamoDBAPI) DescribeTimeToLive(ctx context.Context, input *dynamodb.DescribeTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error) {
+	return &dynamodb.DescribeTimeToLiveOutput{
+		TimeToLiveDescription: &types.TimeToLiveDescription{
+			TimeToLiveStatus: m.status,
+			AttributeName:    aws.String("expire"),
+		},
+	}, nil
+}
+
+func TestSNSLockV2_Verify(t *testing.T) {
+	l := NewSNSLockV2(&describeMockDynamoDBAPI{status: types.TimeToLiveStatusEnabled}, "t1", 900, 0)
+	assert.NoError(t, l.Verify())
+
+	l = NewSNSLockV2(&describeMockDynamoDBAPI{status: types.TimeToLiveStatusDisabled}, "t1", 900, 0)
+	assert.EqualError(t, l.Verify(), "ttl is not enabled on table t1, status: DISABLED")
+}
+
+func TestSNSLockV2_Verify_errorClient(t *testing.T) {
+	l := NewSNSLockV2(&mockDynamoDBPutItemAPI{}, "t1", 900, 0)
+	assert.Error(t, l.Verify())
+}
+
+type getItemMockDynamoDBAPI struct {
+	mockDynamoDBPutItemAPI
+	expire string
+}
+
+func (m *getItemMockDynamoDBAPI) GetItem(ctx context.Context, input *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	return &dynamodb.GetItemOutput{
+		Item: map[string]types.AttributeValue{"expire": &types.AttributeValueMemberN{Value: m.expire}},
+	}, nil
+}
+
+func TestSNSLockV2_AvailableDetailed(t *testing.T) {
+	snsEvent := events.SNSEvent{
+		Records: []events.SNSEventRecord{{SNS: events.SNSEntity{Message: "yolo"}}},
+	}
+
+	l := NewSNSLockV2(&getItemMockDynamoDBAPI{}, "t1", 900, 0)
+	l.nowFunc = func() time.Time { return time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC) }
+
+	result, err := l.AvailableDetailed(snsEvent)
+	assert.NoError(t, err)
+	assert.True(t, result.Acquired)
+	assert.Equal(t, time.Unix(1257894900, 0), result.ExpiresAt)
+}
+
+func TestSNSLockV2_AvailableDetailed_locked(t *testing.T) {
+	snsEvent := events.SNSEvent{
+		Records: []events.SNSEventRecord{{SNS: events.SNSEntity{Message: "yolo"}}},
+	}
+
+	mock := &getItemMockDynamoDBAPI{expire: "1257894600"}
+	mock.failures = 1
+	mock.err = &types.ConditionalCheckFailedException{}
+
+	l := NewSNSLockV2(mock, "t1", 900, 0)
+
+	result, err := l.AvailableDetailed(snsEvent)
+	assert.NoError(t, err)
+	assert.False(t, result.Acquired)
+	assert.Equal(t, time.Unix(1257894600, 0), result.ExpiresAt)
+}
+
+func TestSNSLockV2_AcquireDetailed_errorClient(t *testing.T) {
+	mock := &mockDynamoDBPutItemAPI{failures: 1, err: &types.ConditionalCheckFailedException{}}
+	l := NewSNSLockV2(mock, "t1", 900, 0)
+
+	_, err := l.AcquireDetailed("1234")
+	assert.EqualError(t, err, "dynamodb client does not support GetItem")
+}
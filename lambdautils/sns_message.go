@@ -0,0 +1,34 @@
+package lambdautils
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/pkg/errors"
+)
+
+// UnmarshalSNSMessage unmarshals the json message of the sns event's single
+// record into v. An error is returned if the event doesn't have exactly one
+// record or the message can't be unmarshalled into v.
+//
+// Example:
+//
+//	var order struct {
+//		ID string `json:"id"`
+//	}
+//
+//	err := lambdautils.UnmarshalSNSMessage(snsEvent, &order)
+func UnmarshalSNSMessage(snsEvent events.SNSEvent, v interface{}) error {
+	if len(snsEvent.Records) != 1 {
+		return fmt.Errorf("expected only 1 SNS event, received: %v", len(snsEvent.Records))
+	}
+
+	record := snsEvent.Records[0]
+
+	if err := json.Unmarshal([]byte(record.SNS.Message), v); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal sns message %v into %T", record.SNS.MessageID, v)
+	}
+
+	return nil
+}
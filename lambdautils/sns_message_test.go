@@ -0,0 +1,62 @@
+package lambdautils
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+type namedMessage struct {
+	Name string `json:"name"`
+}
+
+func loadSNSJsonEvent(t *testing.T, count int) events.SNSEvent {
+	b, err := os.ReadFile("testdata/valid_sns_json_event.json")
+	assert.NoError(t, err)
+
+	snsEventRecord := events.SNSEventRecord{}
+	assert.NoError(t, json.Unmarshal(b, &snsEventRecord))
+
+	snsEvent := events.SNSEvent{}
+	for i := 0; i < count; i++ {
+		snsEvent.Records = append(snsEvent.Records, snsEventRecord)
+	}
+
+	return snsEvent
+}
+
+func TestUnmarshalSNSMessage(t *testing.T) {
+	var message namedMessage
+
+	err := UnmarshalSNSMessage(loadSNSJsonEvent(t, 1), &message)
+
+	assert.NoError(t, err)
+	assert.Equal(t, namedMessage{Name: "SomeMessageName"}, message)
+}
+
+func TestUnmarshalSNSMessage_recordCount(t *testing.T) {
+	var message namedMessage
+
+	err := UnmarshalSNSMessage(loadSNSJsonEvent(t, 2), &message)
+	assert.EqualError(t, err, "expected only 1 SNS event, received: 2")
+
+	err = UnmarshalSNSMessage(events.SNSEvent{}, &message)
+	assert.EqualError(t, err, "expected only 1 SNS event, received: 0")
+}
+
+func TestUnmarshalSNSMessage_invalid(t *testing.T) {
+	snsEvent := loadSNSJsonEvent(t, 1)
+	snsEvent.Records[0].SNS.Message = "not json"
+
+	var message namedMessage
+
+	err := UnmarshalSNSMessage(snsEvent, &message)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to unmarshal sns message MESSAGE_ID_PLACEHOLDER into *lambdautils.namedMessage")
+
+	err = UnmarshalSNSMessage(loadSNSJsonEvent(t, 1), message)
+	assert.Error(t, err)
+}
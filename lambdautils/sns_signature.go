@@ -0,0 +1,185 @@
+package lambdautils
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/pkg/errors"
+)
+
+// snsCertHost matches the hosts sns serves its signing certificates from.
+var snsCertHost = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// snsTimestampFormat is the format of sns message timestamps, which always
+// have millisecond precision.
+const snsTimestampFormat = "2006-01-02T15:04:05.000Z"
+
+// defaultSNSVerifier is used by VerifySNSSignature.
+var defaultSNSVerifier = NewSNSVerifier(&http.Client{Timeout: 10 * time.Second})
+
+// SNSVerifier verifies sns message signatures. The signing certificates are
+// fetched with its http client and cached by url, so a verifier should be
+// reused across invocations.
+type SNSVerifier struct {
+	client *http.Client
+	mu     sync.Mutex
+	certs  map[string]*x509.Certificate
+}
+
+// NewSNSVerifier returns an SNSVerifier that fetches signing certificates with
+// the given http client.
+func NewSNSVerifier(client *http.Client) *SNSVerifier {
+	return &SNSVerifier{client: client, certs: map[string]*x509.Certificate{}}
+}
+
+// VerifySNSSignature verifies the signature of the sns record with a shared
+// SNSVerifier, see SNSVerifier.Verify.
+func VerifySNSSignature(record events.SNSEventRecord) error {
+	return defaultSNSVerifier.Verify(record)
+}
+
+// Verify returns an error unless the record's signature was made by sns. The
+// canonical string to sign is built from the record as described in
+// https://docs.aws.amazon.com/sns/latest/dg/sns-verify-signature-of-message.html
+// and checked against the certificate at SigningCertURL, which must be an https
+// url of an sns amazonaws.com host. SignatureVersion 1 (SHA1) and 2 (SHA256)
+// are supported.
+//
+// Only Notification messages, the type delivered to lambda, can be verified.
+// The Timestamp is formatted with millisecond precision, as sns sends it.
+func (verifier *SNSVerifier) Verify(record events.SNSEventRecord) error {
+	entity := record.SNS
+
+	if entity.Type != "Notification" {
+		return fmt.Errorf("unable to verify sns message of type '%s', expected: Notification", entity.Type)
+	}
+
+	var hash crypto.Hash
+	switch entity.SignatureVersion {
+	case "1":
+		hash = crypto.SHA1
+	case "2":
+		hash = crypto.SHA256
+	default:
+		return fmt.Errorf("unsupported sns signature version '%s'", entity.SignatureVersion)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(entity.Signature)
+	if err != nil {
+		return errors.Wrapf(err, "failed decoding signature of sns message %v", entity.MessageID)
+	}
+
+	cert, err := verifier.certificate(entity.SigningCertURL)
+	if err != nil {
+		return err
+	}
+
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("sns signing certificate %v does not have an rsa public key", entity.SigningCertURL)
+	}
+
+	if err := rsa.VerifyPKCS1v15(key, hash, digest(hash, stringToSign(entity)), signature); err != nil {
+		return errors.Wrapf(err, "invalid signature for sns message %v", entity.MessageID)
+	}
+
+	return nil
+}
+
+// stringToSign returns the canonical string sns signs for a notification, the
+// name and value of each field on separate lines in byte order. Subject is only
+// included when present.
+func stringToSign(entity events.SNSEntity) string {
+	var b strings.Builder
+
+	add := func(name string, value string) {
+		b.WriteString(name + "\n" + value + "\n")
+	}
+
+	add("Message", entity.Message)
+	add("MessageId", entity.MessageID)
+
+	if entity.Subject != "" {
+		add("Subject", entity.Subject)
+	}
+
+	add("Timestamp", entity.Timestamp.UTC().Format(snsTimestampFormat))
+	add("TopicArn", entity.TopicArn)
+	add("Type", entity.Type)
+
+	return b.String()
+}
+
+// digest returns the hash of s for the sha1 or sha256 hash.
+func digest(hash crypto.Hash, s string) []byte {
+	if hash == crypto.SHA1 {
+		sum := sha1.Sum([]byte(s))
+		return sum[:]
+	}
+
+	sum := sha256.Sum256([]byte(s))
+	return sum[:]
+}
+
+// certificate returns the signing certificate at the url, fetching it if it
+// isn't cached yet.
+func (verifier *SNSVerifier) certificate(certURL string) (*x509.Certificate, error) {
+	u, err := url.Parse(certURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed parsing sns signing certificate url %v", certURL)
+	}
+
+	if u.Scheme != "https" || !snsCertHost.MatchString(u.Hostname()) {
+		return nil, fmt.Errorf("sns signing certificate url %v is not an https sns amazonaws.com url", certURL)
+	}
+
+	verifier.mu.Lock()
+	defer verifier.mu.Unlock()
+
+	if cert, ok := verifier.certs[certURL]; ok {
+		return cert, nil
+	}
+
+	response, err := verifier.client.Get(certURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed fetching sns signing certificate %v", certURL)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed fetching sns signing certificate %v, status: %v", certURL, response.StatusCode)
+	}
+
+	b, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed reading sns signing certificate %v", certURL)
+	}
+
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("sns signing certificate %v is not pem encoded", certURL)
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed parsing sns signing certificate %v", certURL)
+	}
+
+	verifier.certs[certURL] = cert
+
+	return cert, nil
+}
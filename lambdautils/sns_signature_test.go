@@ -0,0 +1,167 @@
+package lambdautils
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+const testCertURL = "https://sns.us-east-1.amazonaws.com/SimpleNotificationService-test.pem"
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// testSigner returns a signing key and a verifier whose http client serves the
+// key's self signed certificate, counting the fetches.
+func testSigner(t *testing.T) (*rsa.PrivateKey, *SNSVerifier, *int) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	fetches := 0
+
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		fetches++
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewReader(certPEM))}, nil
+	})}
+
+	return key, NewSNSVerifier(client), &fetches
+}
+
+func signedSNSRecord(t *testing.T, key *rsa.PrivateKey, version string) events.SNSEventRecord {
+	record := events.SNSEventRecord{
+		SNS: events.SNSEntity{
+			Type:             "Notification",
+			MessageID:        "22b80b92-fdea-4c2c-8f9d-bdfb0c7bf324",
+			TopicArn:         "arn:aws:sns:us-east-1:123456789012:MilkyWay",
+			Subject:          "yolo",
+			Message:          `{"name":"SomeMessageName"}`,
+			Timestamp:        time.Date(2024, 9, 12, 12, 0, 0, 123000000, time.UTC),
+			SignatureVersion: version,
+			SigningCertURL:   testCertURL,
+		},
+	}
+
+	hash := crypto.SHA1
+	if version == "2" {
+		hash = crypto.SHA256
+	}
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, hash, digest(hash, stringToSign(record.SNS)))
+	assert.NoError(t, err)
+
+	record.SNS.Signature = base64.StdEncoding.EncodeToString(signature)
+
+	return record
+}
+
+func TestStringToSign(t *testing.T) {
+	entity := events.SNSEntity{
+		Type:      "Notification",
+		MessageID: "id",
+		TopicArn:  "arn",
+		Message:   "body",
+		Timestamp: time.Date(2024, 9, 12, 12, 0, 0, 0, time.UTC),
+	}
+
+	assert.Equal(t, "Message\nbody\nMessageId\nid\nTimestamp\n2024-09-12T12:00:00.000Z\nTopicArn\narn\nType\nNotification\n", stringToSign(entity))
+
+	entity.Subject = "subject"
+	assert.Contains(t, stringToSign(entity), "MessageId\nid\nSubject\nsubject\nTimestamp\n")
+}
+
+func TestSNSVerifier_Verify(t *testing.T) {
+	key, verifier, fetches := testSigner(t)
+
+	assert.NoError(t, verifier.Verify(signedSNSRecord(t, key, "1")))
+	assert.NoError(t, verifier.Verify(signedSNSRecord(t, key, "2")))
+	assert.Equal(t, 1, *fetches)
+}
+
+func TestSNSVerifier_Verify_tampered(t *testing.T) {
+	key, verifier, _ := testSigner(t)
+
+	record := signedSNSRecord(t, key, "1")
+	record.SNS.Message = `{"name":"SomeOtherName"}`
+
+	err := verifier.Verify(record)
+	assert.EqualError(t, err, "invalid signature for sns message 22b80b92-fdea-4c2c-8f9d-bdfb0c7bf324: crypto/rsa: verification error")
+
+	record = signedSNSRecord(t, key, "2")
+	record.SNS.Subject = ""
+
+	assert.Error(t, verifier.Verify(record))
+}
+
+func TestSNSVerifier_Verify_errors(t *testing.T) {
+	key, verifier, fetches := testSigner(t)
+
+	record := signedSNSRecord(t, key, "1")
+	record.SNS.SigningCertURL = "https://evil.example.com/sns.amazonaws.com.pem"
+	assert.EqualError(t, verifier.Verify(record), "sns signing certificate url https://evil.example.com/sns.amazonaws.com.pem is not an https sns amazonaws.com url")
+
+	record.SNS.SigningCertURL = "http://sns.us-east-1.amazonaws.com/cert.pem"
+	assert.Error(t, verifier.Verify(record))
+
+	record = signedSNSRecord(t, key, "3")
+	assert.EqualError(t, verifier.Verify(record), "unsupported sns signature version '3'")
+
+	record = signedSNSRecord(t, key, "1")
+	record.SNS.Type = "SubscriptionConfirmation"
+	assert.EqualError(t, verifier.Verify(record), "unable to verify sns message of type 'SubscriptionConfirmation', expected: Notification")
+
+	record = signedSNSRecord(t, key, "1")
+	record.SNS.Signature = "!!!"
+	assert.Error(t, verifier.Verify(record))
+
+	assert.Equal(t, 0, *fetches)
+}
+
+func TestSNSVerifier_Verify_fetchError(t *testing.T) {
+	key, _, _ := testSigner(t)
+
+	verifier := NewSNSVerifier(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 404, Body: io.NopCloser(bytes.NewReader(nil))}, nil
+	})})
+
+	err := verifier.Verify(signedSNSRecord(t, key, "1"))
+	assert.EqualError(t, err, "failed fetching sns signing certificate "+testCertURL+", status: 404")
+
+	verifier = NewSNSVerifier(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewReader([]byte("not a cert")))}, nil
+	})})
+
+	err = verifier.Verify(signedSNSRecord(t, key, "1"))
+	assert.EqualError(t, err, "sns signing certificate "+testCertURL+" is not pem encoded")
+}
+
+func TestVerifySNSSignature(t *testing.T) {
+	err := VerifySNSSignature(events.SNSEventRecord{SNS: events.SNSEntity{Type: "Notification", SignatureVersion: "0"}})
+	assert.EqualError(t, err, "unsupported sns signature version '0'")
+}
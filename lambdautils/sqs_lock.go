@@ -0,0 +1,105 @@
+package lambdautils
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/pkg/errors"
+)
+
+// SQSLock manages locking of sqs messages using dynamodb. By default messages
+// are locked using their MessageId, this can be changed using
+// WithMessageAttribute, e.g. to lock on the MessageDeduplicationId of fifo
+// queues.
+//
+// SQSLock is an IdempotencyLock keyed by the message id, see IdempotencyLock
+// for the configuration options.
+type SQSLock struct {
+	IdempotencyLock
+
+	messageAttribute string
+}
+
+// SQSLockOption configures an SQSLock on construction
+type SQSLockOption func(*SQSLock)
+
+// WithMessageAttribute sets the name of the attribute used as the lock key
+// instead of the MessageId. Message attributes take precedence over the system
+// attributes, such as MessageDeduplicationId.
+func WithMessageAttribute(name string) SQSLockOption {
+	return func(lock *SQSLock) {
+		lock.messageAttribute = name
+	}
+}
+
+// NewSQSLock returns a new sqs lock instance to manage dynamodb locking
+func NewSQSLock(region string, table string, ttl int64, retry int64, opts ...SQSLockOption) *SQSLock {
+	lock := new(SQSLock)
+	lock.IdempotencyLock = *NewIdempotencyLock(region, table, ttl, retry)
+
+	for _, opt := range opts {
+		opt(lock)
+	}
+
+	return lock
+}
+
+// NewSQSLockFromJson returns a new sqs lock instance to manage dynamodb locking
+func NewSQSLockFromJson(s string, opts ...SQSLockOption) (*SQSLock, error) {
+	lock := new(SQSLock)
+
+	err := json.Unmarshal([]byte(s), lock)
+	if err != nil {
+		return nil, err
+	}
+
+	err = lock.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	lock.setDefaults()
+
+	for _, opt := range opts {
+		opt(lock)
+	}
+
+	return lock, nil
+}
+
+// MessageKey returns the lock key of the sqs message, its MessageId or the
+// value of the configured attribute.
+func (lock *SQSLock) MessageKey(record events.SQSMessage) (string, error) {
+	if lock.messageAttribute == "" {
+		if record.MessageId == "" {
+			return "", errors.New("sqs message has no MessageId")
+		}
+
+		return record.MessageId, nil
+	}
+
+	if attr, ok := record.MessageAttributes[lock.messageAttribute]; ok && attr.StringValue != nil && *attr.StringValue != "" {
+		return *attr.StringValue, nil
+	}
+
+	if v := record.Attributes[lock.messageAttribute]; v != "" {
+		return v, nil
+	}
+
+	return "", fmt.Errorf("sqs message %v has no attribute %v", record.MessageId, lock.messageAttribute)
+}
+
+// AvailableSQSRecord returns true if the sqs message is available for use (not
+// locked) and it returns false if it is locked.
+//
+// Locked is defined as the record being in the configured dynamodb table and
+// not expires.
+func (lock *SQSLock) AvailableSQSRecord(record events.SQSMessage) (bool, error) {
+	key, err := lock.MessageKey(record)
+	if err != nil {
+		return false, err
+	}
+
+	return lock.Acquire(key)
+}
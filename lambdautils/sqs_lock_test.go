@@ -0,0 +1,108 @@
+package lambdautils
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func sqsMessage(t *testing.T) events.SQSMessage {
+	b, err := os.ReadFile("testdata/valid_sqs_message.json")
+	assert.NoError(t, err)
+
+	record := events.SQSMessage{}
+	assert.NoError(t, json.Unmarshal(b, &record))
+
+	return record
+}
+
+func TestNewSQSLock(t *testing.T) {
+	l := NewSQSLock("r1", "t1", 0, 0, WithMessageAttribute("OrderId"))
+
+	assert.Equal(t, "r1", l.Region)
+	assert.Equal(t, "t1", l.Table)
+	assert.Equal(t, int64(300), l.TTL)
+	assert.Equal(t, "OrderId", l.messageAttribute)
+}
+
+func TestNewSQSLockFromJson(t *testing.T) {
+	l, err := NewSQSLockFromJson(`{"region": "r1", "table": "t1", "ttl": 900}`)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "r1", l.Region)
+	assert.Equal(t, "t1", l.Table)
+	assert.Equal(t, int64(900), l.TTL)
+	assert.Equal(t, "id", l.KeyAttribute)
+
+	_, err = NewSQSLockFromJson(`{"table": "t1"}`)
+	assert.EqualError(t, err, "region is required")
+}
+
+func TestSQSLock_MessageKey(t *testing.T) {
+	record := sqsMessage(t)
+
+	tests := []struct {
+		attribute string
+		expected  string
+	}{
+		{"", "059f36b4-87a3-44ab-83d2-661975830a7d"},
+		{"MessageDeduplicationId", "dedup-1234"},
+		{"OrderId", "order-42"},
+	}
+
+	for _, test := range tests {
+		l := NewSQSLock("r1", "t1", 900, 0, WithMessageAttribute(test.attribute))
+
+		key, err := l.MessageKey(record)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, key)
+	}
+}
+
+func TestSQSLock_MessageKey_error(t *testing.T) {
+	l := NewSQSLock("r1", "t1", 900, 0, WithMessageAttribute("Missing"))
+
+	_, err := l.MessageKey(sqsMessage(t))
+	assert.EqualError(t, err, "sqs message 059f36b4-87a3-44ab-83d2-661975830a7d has no attribute Missing")
+
+	l = NewSQSLock("r1", "t1", 900, 0)
+
+	_, err = l.MessageKey(events.SQSMessage{})
+	assert.EqualError(t, err, "sqs message has no MessageId")
+}
+
+func TestSQSLock_AvailableSQSRecord(t *testing.T) {
+	mock := &recordingMockDynamoDBClient{}
+
+	l := NewSQSLock("r1", "t1", 900, 0)
+	l.SetClient(mock)
+
+	available, err := l.AvailableSQSRecord(sqsMessage(t))
+	assert.NoError(t, err)
+	assert.True(t, available)
+	assert.Equal(t, "059f36b4-87a3-44ab-83d2-661975830a7d", *mock.input.Item["id"].S)
+	assert.Equal(t, "t1", *mock.input.TableName)
+}
+
+func TestSQSLock_AvailableSQSRecord_locked(t *testing.T) {
+	l := NewSQSLock("r1", "t1", 900, 0, WithMessageAttribute("MessageDeduplicationId"))
+	l.SetClient(&lockedMockDynamoDBClient{locked: map[string]bool{"dedup-1234": true}})
+
+	available, err := l.AvailableSQSRecord(sqsMessage(t))
+	assert.NoError(t, err)
+	assert.False(t, available)
+}
+
+func TestSQSLock_AvailableSQSRecord_error(t *testing.T) {
+	l := NewSQSLock("r1", "t1", 900, 0)
+	l.SetClient(&errorMockDynamoDBClient{})
+
+	_, err := l.AvailableSQSRecord(sqsMessage(t))
+	assert.Error(t, err)
+
+	_, err = l.AvailableSQSRecord(events.SQSMessage{})
+	assert.Error(t, err)
+}
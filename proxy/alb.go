@@ -0,0 +1,124 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// ALBToV2Request converts an alb target group request into the api gateway v2
+// (http) request shape used by the router.
+//
+// As with api gateway v2, header names are lowercased and multi-value headers
+// and query string parameters are comma joined. The alb passes query string
+// parameters through url encoded, so they are decoded.
+func ALBToV2Request(request events.ALBTargetGroupRequest) events.APIGatewayV2HTTPRequest {
+	single := decodeValues(request.QueryStringParameters)
+	multi := decodeMultiValues(request.MultiValueQueryStringParameters)
+
+	return events.APIGatewayV2HTTPRequest{
+		RawPath:               request.Path,
+		RawQueryString:        rawQueryString(single, multi),
+		Headers:               joinHeaders(request.Headers, request.MultiValueHeaders),
+		QueryStringParameters: joinValues(single, multi),
+		Body:                  request.Body,
+		IsBase64Encoded:       request.IsBase64Encoded,
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method: request.HTTPMethod,
+				Path:   request.Path,
+			},
+		},
+	}
+}
+
+// ProxyResponseToALBResponse converts a router response into an alb target
+// group response. The alb requires a status description, which is derived from
+// the status code, e.g. "404 Not Found".
+//
+// When multiValue is true, as required when the target group has multi-value
+// headers enabled, all headers are returned via MultiValueHeaders.
+func ProxyResponseToALBResponse(response events.APIGatewayProxyResponse, multiValue bool) events.ALBTargetGroupResponse {
+	alb := events.ALBTargetGroupResponse{
+		StatusCode:        response.StatusCode,
+		StatusDescription: fmt.Sprintf("%d %s", response.StatusCode, http.StatusText(response.StatusCode)),
+		Headers:           response.Headers,
+		MultiValueHeaders: response.MultiValueHeaders,
+		Body:              response.Body,
+		IsBase64Encoded:   response.IsBase64Encoded,
+	}
+
+	if !multiValue {
+		return alb
+	}
+
+	headers := map[string][]string{}
+
+	for k, v := range response.Headers {
+		headers[k] = []string{v}
+	}
+
+	for k, v := range response.MultiValueHeaders {
+		headers[k] = append(headers[k], v...)
+	}
+
+	alb.Headers = nil
+	alb.MultiValueHeaders = headers
+
+	return alb
+}
+
+// RouteALB routes an alb target group request by converting it with
+// ALBToV2Request and passing it to Route. The response is converted with
+// ProxyResponseToALBResponse, using multi-value headers when the request did.
+func (router *Router) RouteALB(ctx context.Context, request events.ALBTargetGroupRequest) (events.ALBTargetGroupResponse, error) {
+	response, err := router.Route(ctx, ALBToV2Request(request))
+
+	return ProxyResponseToALBResponse(response, request.MultiValueHeaders != nil), err
+}
+
+// decodeValues url decodes the values, keeping any value that fails to decode
+// as is.
+func decodeValues(values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+
+	decoded := map[string]string{}
+	for k, v := range values {
+		decoded[decodeValue(k)] = decodeValue(v)
+	}
+
+	return decoded
+}
+
+// decodeMultiValues url decodes the values, keeping any value that fails to
+// decode as is.
+func decodeMultiValues(values map[string][]string) map[string][]string {
+	if values == nil {
+		return nil
+	}
+
+	decoded := map[string][]string{}
+	for k, vs := range values {
+		key := decodeValue(k)
+		for _, v := range vs {
+			decoded[key] = append(decoded[key], decodeValue(v))
+		}
+	}
+
+	return decoded
+}
+
+// decodeValue url decodes v, returning v as is if it fails to decode.
+func decodeValue(v string) string {
+	decoded, err := url.QueryUnescape(v)
+	if err != nil {
+		return v
+	}
+
+	return decoded
+}
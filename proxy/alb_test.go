@@ -0,0 +1,97 @@
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestALBToV2Request(t *testing.T) {
+	request := ALBToV2Request(dummyALBTargetGroupRequest("alb"))
+
+	assert.Equal(t, "/wowza/42", request.RawPath)
+	assert.Equal(t, "GET", request.RequestContext.HTTP.Method)
+	assert.Equal(t, "jones=arm+leg", request.RawQueryString)
+	assert.Equal(t, map[string]string{"jones": "arm leg"}, request.QueryStringParameters)
+	assert.Equal(t, "application/json", request.Headers["accept"])
+}
+
+func TestALBToV2Request_multiValue(t *testing.T) {
+	request := ALBToV2Request(events.ALBTargetGroupRequest{
+		HTTPMethod:                      "GET",
+		Path:                            "/",
+		MultiValueQueryStringParameters: map[string][]string{"tag": {"a", "b%2Cc"}, "bad": {"%ZZ"}},
+		MultiValueHeaders:               map[string][]string{"X-Custom": {"one", "two"}},
+	})
+
+	assert.Equal(t, map[string]string{"tag": "a,b,c", "bad": "%ZZ"}, request.QueryStringParameters)
+	assert.Equal(t, "one,two", request.Headers["x-custom"])
+}
+
+func TestProxyResponseToALBResponse(t *testing.T) {
+	response := ProxyResponseToALBResponse(events.APIGatewayProxyResponse{
+		StatusCode: 201,
+		Headers:    map[string]string{"Content-Type": "text/plain"},
+		Body:       "created",
+	}, false)
+
+	assert.Equal(t, 201, response.StatusCode)
+	assert.Equal(t, "201 Created", response.StatusDescription)
+	assert.Equal(t, map[string]string{"Content-Type": "text/plain"}, response.Headers)
+	assert.Nil(t, response.MultiValueHeaders)
+	assert.Equal(t, "created", response.Body)
+}
+
+func TestProxyResponseToALBResponse_multiValue(t *testing.T) {
+	response := ProxyResponseToALBResponse(events.APIGatewayProxyResponse{
+		StatusCode:        200,
+		Headers:           map[string]string{"Content-Type": "text/plain"},
+		MultiValueHeaders: map[string][]string{"Set-Cookie": {"a=1", "b=2"}},
+	}, true)
+
+	expected := map[string][]string{
+		"Content-Type": {"text/plain"},
+		"Set-Cookie":   {"a=1", "b=2"},
+	}
+
+	assert.Equal(t, "200 OK", response.StatusDescription)
+	assert.Nil(t, response.Headers)
+	assert.Equal(t, expected, response.MultiValueHeaders)
+}
+
+func TestRouter_RouteALB(t *testing.T) {
+	r := &Router{}
+
+	routeHandler := func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Body:       ctx.Params["id"] + " " + ctx.Params["jones"],
+		}, nil
+	}
+
+	r.GET("/wowza/(?P<id>[0-9]+)", routeHandler)
+
+	response, err := r.RouteALB(context.Background(), dummyALBTargetGroupRequest("alb"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, "200 OK", response.StatusDescription)
+	assert.Equal(t, "42 arm leg", response.Body)
+}
+
+func TestRouter_RouteALB_notFound(t *testing.T) {
+	r := &Router{}
+
+	r.AddCatchAllHandler(func(ctx context.Context, request events.APIGatewayV2HTTPRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: 404, Body: "not found"}, nil
+	})
+
+	response, err := r.RouteALB(context.Background(), dummyALBTargetGroupRequest("alb"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 404, response.StatusCode)
+	assert.Equal(t, "404 Not Found", response.StatusDescription)
+	assert.Equal(t, "not found", response.Body)
+}
@@ -0,0 +1,105 @@
+package proxy
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// V1ToV2Request converts an api gateway v1 (rest) request into the api gateway
+// v2 (http) request shape used by the router.
+//
+// As with api gateway v2, header names are lowercased and multi-value headers
+// and query string parameters are comma joined.
+func V1ToV2Request(request events.APIGatewayProxyRequest) events.APIGatewayV2HTTPRequest {
+	rc := request.RequestContext
+
+	return events.APIGatewayV2HTTPRequest{
+		Version:               "1.0",
+		RawPath:               request.Path,
+		RawQueryString:        rawQueryString(request.QueryStringParameters, request.MultiValueQueryStringParameters),
+		Headers:               joinHeaders(request.Headers, request.MultiValueHeaders),
+		QueryStringParameters: joinValues(request.QueryStringParameters, request.MultiValueQueryStringParameters),
+		PathParameters:        request.PathParameters,
+		StageVariables:        request.StageVariables,
+		Body:                  request.Body,
+		IsBase64Encoded:       request.IsBase64Encoded,
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			AccountID:    rc.AccountID,
+			Stage:        rc.Stage,
+			RequestID:    rc.RequestID,
+			APIID:        rc.APIID,
+			DomainName:   rc.DomainName,
+			DomainPrefix: rc.DomainPrefix,
+			Time:         rc.RequestTime,
+			TimeEpoch:    rc.RequestTimeEpoch,
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method:    request.HTTPMethod,
+				Path:      request.Path,
+				Protocol:  rc.Protocol,
+				SourceIP:  rc.Identity.SourceIP,
+				UserAgent: rc.Identity.UserAgent,
+			},
+		},
+	}
+}
+
+// RouteV1 routes an api gateway v1 (rest) request by converting it with
+// V1ToV2Request and passing it to Route.
+func (router *Router) RouteV1(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	return router.Route(ctx, V1ToV2Request(request))
+}
+
+// joinHeaders merges the single and multi-value headers into a single map with
+// lowercased names and comma joined values.
+func joinHeaders(single map[string]string, multi map[string][]string) map[string]string {
+	headers := map[string]string{}
+
+	for k, v := range single {
+		headers[strings.ToLower(k)] = v
+	}
+
+	for k, v := range multi {
+		headers[strings.ToLower(k)] = strings.Join(v, ",")
+	}
+
+	return headers
+}
+
+// joinValues merges the single and multi-value parameters into a single map
+// with comma joined values. Nil is returned when there are no parameters.
+func joinValues(single map[string]string, multi map[string][]string) map[string]string {
+	if len(single) == 0 && len(multi) == 0 {
+		return nil
+	}
+
+	values := map[string]string{}
+
+	for k, v := range single {
+		values[k] = v
+	}
+
+	for k, v := range multi {
+		values[k] = strings.Join(v, ",")
+	}
+
+	return values
+}
+
+// rawQueryString builds an encoded query string from the single and
+// multi-value query string parameters.
+func rawQueryString(single map[string]string, multi map[string][]string) string {
+	values := url.Values{}
+
+	for k, v := range single {
+		values.Set(k, v)
+	}
+
+	for k, v := range multi {
+		values[k] = v
+	}
+
+	return values.Encode()
+}
@@ -0,0 +1,64 @@
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestV1ToV2Request(t *testing.T) {
+	request := V1ToV2Request(dummyAPIGatewayProxyRequest("v1"))
+
+	assert.Equal(t, "/wowza/42", request.RawPath)
+	assert.Equal(t, "GET", request.RequestContext.HTTP.Method)
+	assert.Equal(t, "1.1.1.1", request.RequestContext.HTTP.SourceIP)
+	assert.Equal(t, "curl/7.79.1", request.RequestContext.HTTP.UserAgent)
+	assert.Equal(t, "prod", request.RequestContext.Stage)
+	assert.Equal(t, "c6af9ac6-7b61-11e6-9a41-93e8deadbeef", request.RequestContext.RequestID)
+	assert.Equal(t, "jones=arm&tag=a&tag=b", request.RawQueryString)
+	assert.Equal(t, map[string]string{"jones": "arm", "tag": "a,b"}, request.QueryStringParameters)
+	assert.Equal(t, map[string]string{"proxy": "wowza/42"}, request.PathParameters)
+	assert.Equal(t, "application/json", request.Headers["accept"])
+	assert.Equal(t, "one,two", request.Headers["x-custom"])
+	assert.NotContains(t, request.Headers, "Accept")
+}
+
+func TestV1ToV2Request_empty(t *testing.T) {
+	request := V1ToV2Request(events.APIGatewayProxyRequest{HTTPMethod: "POST", Path: "/"})
+
+	assert.Equal(t, "/", request.RawPath)
+	assert.Equal(t, "POST", request.RequestContext.HTTP.Method)
+	assert.Equal(t, "", request.RawQueryString)
+	assert.Nil(t, request.QueryStringParameters)
+	assert.Empty(t, request.Headers)
+}
+
+func TestRouter_RouteV1(t *testing.T) {
+	r := &Router{}
+
+	routeHandler := func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Body:       ctx.Params["id"] + " " + ctx.Params["jones"],
+		}, nil
+	}
+
+	r.GET("/wowza/(?P<id>[0-9]+)", routeHandler)
+
+	response, err := r.RouteV1(context.Background(), dummyAPIGatewayProxyRequest("v1"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, "42 arm", response.Body)
+}
+
+func TestRouter_RouteV1_notFound(t *testing.T) {
+	r := &Router{}
+
+	_, err := r.RouteV1(context.Background(), events.APIGatewayProxyRequest{HTTPMethod: "GET", Path: "/nope"})
+
+	assert.Error(t, err)
+	assert.Equal(t, "'GET /nope' not found", err.Error())
+}
@@ -0,0 +1,67 @@
+package proxy
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// BasicAuth returns middleware that requires HTTP Basic authentication. The
+// user and password of the request's 'Authorization: Basic ...' header are
+// passed to check and the handler is only called if it returns true. A missing
+// or malformed header, or failed check, receives a 401 response with a
+// WWW-Authenticate header for the realm.
+//
+// check should compare secrets in constant time, such as with SecureCompare.
+//
+// Example:
+//
+//	router.Use(BasicAuth("internal", func(user, pass string) bool {
+//		return SecureCompare(user, "admin") && SecureCompare(pass, os.Getenv("ADMIN_PASSWORD"))
+//	}))
+func BasicAuth(realm string, check func(user, pass string) bool) Middleware {
+	challenge := `Basic realm="` + strings.ReplaceAll(realm, `"`, `\"`) + `", charset="UTF-8"`
+
+	return func(next RouteHandler) RouteHandler {
+		return func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+			user, pass, ok := basicCredentials(ctx)
+			if !ok || !check(user, pass) {
+				response, err := Text(401, "unauthorized")
+				response.Headers["WWW-Authenticate"] = challenge
+				return response, err
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// SecureCompare returns true if a and b are equal. The comparison takes the
+// same time regardless of where the strings differ, so it doesn't leak the
+// secret through timing. Only the length of the strings may be leaked.
+func SecureCompare(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// basicCredentials returns the user and password of the request's basic
+// authorization header, or false if it is missing or malformed.
+func basicCredentials(ctx *RouteContext) (string, string, bool) {
+	encoded, ok := ctx.authorization("basic")
+	if !ok {
+		return "", "", false
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", "", false
+	}
+
+	user, pass, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return "", "", false
+	}
+
+	return user, pass, true
+}
@@ -0,0 +1,72 @@
+package proxy
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func basicAuthRouter() *Router {
+	r := &Router{}
+	r.Use(BasicAuth("internal", func(user, pass string) bool {
+		return SecureCompare(user, "admin") && SecureCompare(pass, "s3cr:t")
+	}))
+	r.GET("/yolo", testHandler)
+
+	return r
+}
+
+func basicAuthRequest(authorization string) events.APIGatewayV2HTTPRequest {
+	request := testRequest(GET, "/yolo")
+	if authorization != "" {
+		request.Headers["authorization"] = authorization
+	}
+
+	return request
+}
+
+func TestBasicAuth(t *testing.T) {
+	credentials := base64.StdEncoding.EncodeToString([]byte("admin:s3cr:t"))
+
+	response, err := basicAuthRouter().Route(context.Background(), basicAuthRequest("Basic "+credentials))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+
+	response, err = basicAuthRouter().Route(context.Background(), basicAuthRequest("basic "+credentials))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+}
+
+func TestBasicAuth_badCredentials(t *testing.T) {
+	for _, authorization := range []string{
+		"Basic " + base64.StdEncoding.EncodeToString([]byte("admin:wrong")),
+		"Basic " + base64.StdEncoding.EncodeToString([]byte("admin")),
+		"Basic !!!",
+		"Basic",
+		"Bearer " + base64.StdEncoding.EncodeToString([]byte("admin:s3cr:t")),
+	} {
+		response, err := basicAuthRouter().Route(context.Background(), basicAuthRequest(authorization))
+		assert.NoError(t, err)
+		assert.Equal(t, 401, response.StatusCode, authorization)
+		assert.Equal(t, `Basic realm="internal", charset="UTF-8"`, response.Headers["WWW-Authenticate"])
+	}
+}
+
+func TestBasicAuth_missingHeader(t *testing.T) {
+	response, err := basicAuthRouter().Route(context.Background(), basicAuthRequest(""))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 401, response.StatusCode)
+	assert.Equal(t, "unauthorized", response.Body)
+	assert.Equal(t, `Basic realm="internal", charset="UTF-8"`, response.Headers["WWW-Authenticate"])
+}
+
+func TestSecureCompare(t *testing.T) {
+	assert.True(t, SecureCompare("s3cret", "s3cret"))
+	assert.False(t, SecureCompare("s3cret", "s3creT"))
+	assert.False(t, SecureCompare("s3cret", "s3cre"))
+	assert.True(t, SecureCompare("", ""))
+}
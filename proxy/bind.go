@@ -0,0 +1,161 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Validator is implemented by bound values that validate themselves. BindJSON
+// and BindForm call Validate once the body has been decoded into the value, so
+// handlers receive either a valid value or an error.
+type Validator interface {
+	Validate() error
+}
+
+// BindJSON decodes the json request body, base64 decoding it if required, into
+// v. If v implements Validator it is validated after decoding and the
+// validation error is returned wrapped.
+//
+// Example:
+//
+//	var signup struct {
+//		Email string `json:"email"`
+//	}
+//
+//	err := ctx.BindJSON(&signup)
+func (ctx *RouteContext) BindJSON(v interface{}) error {
+	body, err := ctx.BodyBytes()
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(body, v)
+	if err != nil {
+		return errors.Wrap(err, "unable to parse json body")
+	}
+
+	return validate(v)
+}
+
+// BindForm parses the urlencoded or multipart form request body, as returned by
+// Form, into the struct pointed to by v. Fields are mapped by their
+// `form:"name"` tag, fields without a tag and unknown form keys are ignored.
+// String, int, uint, float and bool fields are supported, and an error is
+// returned if the body isn't a form or a value can't be converted to its
+// field's kind. If v implements Validator it is validated after binding. A body
+// sent without a Content-Type header is parsed as a urlencoded form.
+//
+// Example:
+//
+//	var signup struct {
+//		Email string `form:"email"`
+//		Age   int    `form:"age"`
+//	}
+//
+//	err := ctx.BindForm(&signup)
+func (ctx *RouteContext) BindForm(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind form requires a non-nil struct pointer, received %T", v)
+	}
+
+	form, err := ctx.bindableForm()
+	if err != nil {
+		return errors.Wrap(err, "unable to parse form body")
+	}
+
+	rv = rv.Elem()
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+
+		name := field.Tag.Get("form")
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+
+		values, ok := form[name]
+		if !ok || len(values) == 0 {
+			continue
+		}
+
+		err := setField(rv.Field(i), values[0])
+		if err != nil {
+			return errors.Wrapf(err, "unable to bind form field '%s'", name)
+		}
+	}
+
+	return validate(v)
+}
+
+// bindableForm returns the form values of the body, see Form, treating a body
+// without a Content-Type header as a urlencoded form.
+func (ctx *RouteContext) bindableForm() (url.Values, error) {
+	if ctx.Header("content-type") != "" {
+		return ctx.Form()
+	}
+
+	body, err := ctx.BodyBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	return parseURLEncodedForm(string(body))
+}
+
+// validate calls Validate if v implements Validator.
+func validate(v interface{}) error {
+	validator, ok := v.(Validator)
+	if !ok {
+		return nil
+	}
+
+	err := validator.Validate()
+	if err != nil {
+		return errors.Wrap(err, "validation failed")
+	}
+
+	return nil
+}
+
+// setField converts the string value to the field's kind and sets it.
+func setField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported field kind %s", field.Kind())
+	}
+
+	return nil
+}
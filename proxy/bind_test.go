@@ -0,0 +1,183 @@
+package proxy
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type signupForm struct {
+	Email    string  `form:"email"`
+	Age      int     `form:"age"`
+	Count    uint8   `form:"count"`
+	Score    float64 `form:"score"`
+	Admin    bool    `form:"admin"`
+	Ignored  string
+	Skipped  string `form:"-"`
+	internal string `form:"internal"`
+}
+
+func formContext(body string, base64Encoded bool) *RouteContext {
+	request := testRequest(POST, "/signup")
+	request.Headers["content-type"] = "application/x-www-form-urlencoded"
+	request.Body = body
+	request.IsBase64Encoded = base64Encoded
+
+	return newRouteContext(context.Background(), request, map[string]string{})
+}
+
+func TestRouteContext_BindForm(t *testing.T) {
+	ctx := formContext("email=yolo%40example.com&age=42&count=7&score=1.5&admin=true&unknown=x&Ignored=y&-=z&internal=w", false)
+
+	var form signupForm
+	assert.NoError(t, ctx.BindForm(&form))
+
+	expected := signupForm{
+		Email: "yolo@example.com",
+		Age:   42,
+		Count: 7,
+		Score: 1.5,
+		Admin: true,
+	}
+
+	assert.Equal(t, expected, form)
+}
+
+func TestRouteContext_BindForm_base64(t *testing.T) {
+	body := base64.StdEncoding.EncodeToString([]byte("email=a+b%40example.com&age=7"))
+	ctx := formContext(body, true)
+
+	var form signupForm
+	assert.NoError(t, ctx.BindForm(&form))
+	assert.Equal(t, "a b@example.com", form.Email)
+	assert.Equal(t, 7, form.Age)
+}
+
+func TestRouteContext_BindForm_missing(t *testing.T) {
+	ctx := formContext("email=yolo%40example.com", false)
+
+	form := signupForm{Age: 3}
+	assert.NoError(t, ctx.BindForm(&form))
+	assert.Equal(t, "yolo@example.com", form.Email)
+	assert.Equal(t, 3, form.Age)
+}
+
+func TestRouteContext_BindForm_errors(t *testing.T) {
+	var form signupForm
+
+	err := formContext("age=old", false).BindForm(&form)
+	assert.EqualError(t, err, `unable to bind form field 'age': strconv.ParseInt: parsing "old": invalid syntax`)
+
+	err = formContext("count=300", false).BindForm(&form)
+	assert.Error(t, err)
+
+	err = formContext("admin=maybe", false).BindForm(&form)
+	assert.Error(t, err)
+
+	err = formContext("age=%zz", false).BindForm(&form)
+	assert.Error(t, err)
+
+	err = formContext("!!!", true).BindForm(&form)
+	assert.Error(t, err)
+
+	err = formContext("age=1", false).BindForm(form)
+	assert.Error(t, err)
+
+	var unsupported struct {
+		Tags []string `form:"tags"`
+	}
+
+	err = formContext("tags=a", false).BindForm(&unsupported)
+	assert.EqualError(t, err, "unable to bind form field 'tags': unsupported field kind slice")
+}
+
+type validatedSignup struct {
+	Email string `json:"email" form:"email"`
+}
+
+func (s *validatedSignup) Validate() error {
+	if s.Email == "" {
+		return errors.New("email is required")
+	}
+
+	return nil
+}
+
+func jsonContext(body string) *RouteContext {
+	request := testRequest(POST, "/signup")
+	request.Headers["content-type"] = "application/json"
+	request.Body = body
+
+	return newRouteContext(context.Background(), request, map[string]string{})
+}
+
+func TestRouteContext_BindJSON(t *testing.T) {
+	var signup validatedSignup
+	assert.NoError(t, jsonContext(`{"email":"yolo@example.com"}`).BindJSON(&signup))
+	assert.Equal(t, "yolo@example.com", signup.Email)
+
+	var plain struct {
+		Age int `json:"age"`
+	}
+	assert.NoError(t, jsonContext(`{"age":42}`).BindJSON(&plain))
+	assert.Equal(t, 42, plain.Age)
+}
+
+func TestRouteContext_BindJSON_validationFails(t *testing.T) {
+	var signup validatedSignup
+	err := jsonContext(`{"email":""}`).BindJSON(&signup)
+	assert.EqualError(t, err, "validation failed: email is required")
+
+	err = jsonContext(`{"email":`).BindJSON(&signup)
+	assert.EqualError(t, err, "unable to parse json body: unexpected end of JSON input")
+}
+
+func TestRouteContext_BindForm_validator(t *testing.T) {
+	var signup validatedSignup
+	assert.NoError(t, formContext("email=yolo%40example.com", false).BindForm(&signup))
+	assert.Equal(t, "yolo@example.com", signup.Email)
+
+	var empty validatedSignup
+	err := formContext("age=1", false).BindForm(&empty)
+	assert.EqualError(t, err, "validation failed: email is required")
+}
+
+func TestRouteContext_BindForm_matchesParams(t *testing.T) {
+	ctx := formContext("email=yolo%40example.com&age", false)
+
+	var form signupForm
+	bindErr := ctx.BindForm(&form)
+	assert.EqualError(t, bindErr, "unable to parse form body: invalid key/value pair 'age' in form post")
+
+	route, err := NewRoute(POST, "/signup", testHandler)
+	assert.NoError(t, err)
+
+	paramsErr := route.extractParamsFromFormPost(map[string]string{}, ctx.Request)
+	assert.Error(t, paramsErr)
+	assert.Contains(t, paramsErr.Error(), "invalid key/value pair 'age' in form post")
+}
+
+func TestRouteContext_BindForm_noContentType(t *testing.T) {
+	ctx := formContext("email=yolo%40example.com&age=42", false)
+	delete(ctx.Request.Headers, "content-type")
+
+	var form signupForm
+	assert.NoError(t, ctx.BindForm(&form))
+	assert.Equal(t, "yolo@example.com", form.Email)
+	assert.Equal(t, 42, form.Age)
+
+	_, err := ctx.Form()
+	assert.Error(t, err)
+}
+
+func TestRouteContext_BindForm_contentType(t *testing.T) {
+	ctx := formContext("email=yolo%40example.com", false)
+	ctx.Request.Headers["content-type"] = "application/json"
+
+	var form signupForm
+	err := ctx.BindForm(&form)
+	assert.EqualError(t, err, "unable to parse form body: unsupported form content type 'application/json'")
+}
@@ -0,0 +1,46 @@
+package proxy
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// SetMaxBodyBytes sets the maximum decoded request body size. Requests with a
+// larger body receive a 413 response without a handler being executed. Zero,
+// the default, disables the limit.
+func (router *Router) SetMaxBodyBytes(n int64) {
+	router.maxBodyBytes = n
+}
+
+// bodyTooLarge returns true if the request body exceeds the maximum body size.
+func (router *Router) bodyTooLarge(request events.APIGatewayV2HTTPRequest) bool {
+	return router.maxBodyBytes > 0 && bodySize(request) > router.maxBodyBytes
+}
+
+// bodySize returns the decoded size of the request body. For base64 encoded
+// bodies the size is calculated from the encoded length, without decoding.
+func bodySize(request events.APIGatewayV2HTTPRequest) int64 {
+	if !request.IsBase64Encoded {
+		return int64(len(request.Body))
+	}
+
+	padding := len(request.Body) - len(strings.TrimRight(request.Body, "="))
+	if padding > 2 {
+		padding = 2
+	}
+
+	if padding > 0 || len(request.Body)%4 == 0 {
+		return int64(base64.StdEncoding.DecodedLen(len(request.Body)) - padding)
+	}
+
+	return int64(base64.RawStdEncoding.DecodedLen(len(request.Body)))
+}
+
+// bodyTooLargeResponse returns the 413 response for a request body that exceeds
+// the maximum body size.
+func bodyTooLargeResponse() events.APIGatewayProxyResponse {
+	response, _ := Text(413, "request body too large")
+	return response
+}
@@ -0,0 +1,87 @@
+package proxy
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodySize(t *testing.T) {
+	for n := 0; n < 10; n++ {
+		data := []byte(strings.Repeat("x", n))
+
+		request := testRequest(POST, "/upload")
+		request.Body = string(data)
+		assert.Equal(t, int64(n), bodySize(request))
+
+		request.IsBase64Encoded = true
+		request.Body = base64.StdEncoding.EncodeToString(data)
+		assert.Equal(t, int64(n), bodySize(request), request.Body)
+
+		request.Body = base64.RawStdEncoding.EncodeToString(data)
+		assert.Equal(t, int64(n), bodySize(request), request.Body)
+	}
+}
+
+func TestRouter_SetMaxBodyBytes(t *testing.T) {
+	r := &Router{}
+	r.SetMaxBodyBytes(8)
+	r.POST("/upload", testHandler)
+
+	request := testRequest(POST, "/upload")
+	request.Body = "12345678"
+
+	response, err := r.Route(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+
+	request.Body = "123456789"
+
+	response, err = r.Route(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, 413, response.StatusCode)
+}
+
+func TestRouter_SetMaxBodyBytes_base64(t *testing.T) {
+	called := false
+
+	r := &Router{}
+	r.SetMaxBodyBytes(8)
+	r.POST("/upload", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		called = true
+		return testHandler(ctx)
+	})
+
+	request := testRequest(POST, "/upload")
+	request.IsBase64Encoded = true
+	request.Body = base64.StdEncoding.EncodeToString([]byte("12345678"))
+
+	response, err := r.Route(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.True(t, called)
+
+	called = false
+	request.Body = base64.StdEncoding.EncodeToString([]byte("123456789"))
+
+	response, err = r.Route(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, 413, response.StatusCode)
+	assert.False(t, called)
+}
+
+func TestRouter_SetMaxBodyBytes_disabled(t *testing.T) {
+	r := &Router{}
+	r.POST("/upload", testHandler)
+
+	request := testRequest(POST, "/upload")
+	request.Body = strings.Repeat("x", 1<<20)
+
+	response, err := r.Route(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+}
@@ -0,0 +1,126 @@
+package proxy
+
+import (
+	"regexp/syntax"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// matcher is the compiled form of the router's routes, see Compile.
+type matcher struct {
+	routes []*Route
+	static map[string]int
+	regex  []int
+}
+
+// Compile optimises route matching for routers with many routes by looking up
+// routes whose pattern is a plain literal path, such as '/users/new', in a map
+// keyed by method and path. Only the remaining routes are matched by regex.
+//
+// Routing decisions are unchanged: a route is only matched via the map if no
+// regex route added before it matches. Adding, removing or sorting routes
+// discards the compiled matcher, so Compile should be called once all routes
+// have been added.
+func (router *Router) Compile() {
+	m := &matcher{
+		routes: router.Routes,
+		static: make(map[string]int),
+	}
+
+	for i, route := range router.Routes {
+		path, lenient, ok := route.staticPath()
+		if !ok {
+			m.regex = append(m.regex, i)
+			continue
+		}
+
+		keys := []string{path}
+		if lenient {
+			keys = append(keys, path+"/")
+		}
+
+		for _, key := range keys {
+			key = staticKey(route.Method.String(), key)
+			if _, exists := m.static[key]; !exists {
+				m.static[key] = i
+			}
+		}
+	}
+
+	router.matcher = m
+}
+
+// staticKey returns the key of the method and path in the static route map.
+func staticKey(method string, path string) string {
+	return method + " " + path
+}
+
+// staticPath returns the literal path of a route whose pattern only matches a
+// single path, optionally with a trailing slash if lenient is set. ok is false
+// if the route's pattern is dynamic.
+func (route *Route) staticPath() (path string, lenient bool, ok bool) {
+	if route.MatchQuery {
+		return "", false, false
+	}
+
+	re, err := syntax.Parse(route.Regex.String(), syntax.Perl)
+	if err != nil || re.Op != syntax.OpConcat {
+		return "", false, false
+	}
+
+	subs := re.Sub
+	if len(subs) < 3 || subs[0].Op != syntax.OpBeginText || subs[len(subs)-1].Op != syntax.OpEndText {
+		return "", false, false
+	}
+
+	subs = subs[1 : len(subs)-1]
+
+	if last := subs[len(subs)-1]; last.Op == syntax.OpQuest && isLiteral(last.Sub[0], "/") {
+		lenient = true
+		subs = subs[:len(subs)-1]
+	}
+
+	if len(subs) != 1 || subs[0].Op != syntax.OpLiteral || subs[0].Flags&syntax.FoldCase != 0 {
+		return "", false, false
+	}
+
+	return string(subs[0].Rune), lenient, true
+}
+
+// isLiteral returns true if re is the case sensitive literal s.
+func isLiteral(re *syntax.Regexp, s string) bool {
+	return re.Op == syntax.OpLiteral && re.Flags&syntax.FoldCase == 0 && string(re.Rune) == s
+}
+
+// match returns the first route, in matching order, that matches the request
+// along with its match groups, or nil if no route matches.
+func (router *Router) match(request events.APIGatewayV2HTTPRequest) (*Route, []string) {
+	m := router.matcher
+	if m == nil || len(m.routes) != len(router.Routes) {
+		for _, route := range router.Routes {
+			if matched, groups := route.IsMatch(request); matched {
+				return route, groups
+			}
+		}
+
+		return nil, nil
+	}
+
+	static, ok := m.static[staticKey(request.RequestContext.HTTP.Method, request.RawPath)]
+
+	for _, i := range m.regex {
+		if ok && i > static {
+			break
+		}
+
+		if matched, groups := m.routes[i].IsMatch(request); matched {
+			return m.routes[i], groups
+		}
+	}
+
+	if ok {
+		return m.routes[static], []string{request.RawPath}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,181 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoute_staticPath(t *testing.T) {
+	tests := []struct {
+		route   func() (*Route, error)
+		path    string
+		lenient bool
+		ok      bool
+	}{
+		{func() (*Route, error) { return NewRoute(GET, "/users/new", testHandler) }, "/users/new", true, true},
+		{func() (*Route, error) { return NewRoute(GET, "/", testHandler) }, "/", true, true},
+		{func() (*Route, error) { return NewRouteRaw(GET, "^/users$", testHandler) }, "/users", false, true},
+		{func() (*Route, error) { return NewRoute(GET, `/v1/items\.json`, testHandler) }, "/v1/items.json", true, true},
+		{func() (*Route, error) { return NewRoute(GET, "/users/:id", testHandler) }, "", false, false},
+		{func() (*Route, error) { return NewRoute(GET, "(?i)/users", testHandler) }, "", false, false},
+		{func() (*Route, error) { return NewRoute(GET, "/users?", testHandler) }, "", false, false},
+		{func() (*Route, error) { return NewRouteRaw(GET, "/users", testHandler) }, "", false, false},
+		{func() (*Route, error) { return NewQueryRoute(GET, `/search\?q=yolo`, testHandler) }, "", false, false},
+	}
+
+	for _, test := range tests {
+		route, err := test.route()
+		assert.NoError(t, err)
+
+		path, lenient, ok := route.staticPath()
+		assert.Equal(t, test.ok, ok, route.String())
+		assert.Equal(t, test.path, path, route.String())
+		assert.Equal(t, test.lenient, lenient, route.String())
+	}
+}
+
+func namedHandler(name string) RouteHandler {
+	return func(*RouteContext) (events.APIGatewayProxyResponse, error) {
+		return Text(200, name)
+	}
+}
+
+func compileTestRouter() *Router {
+	r := &Router{}
+	r.GET("/users/(?P<id>new|edit)", namedHandler("regex new"))
+	r.GET("/users/new", namedHandler("static new"))
+	r.GET("/users/all", namedHandler("static all"))
+	r.GET("/users/:id", namedHandler("regex id"))
+	r.GET("/users/all/extra", namedHandler("static extra"))
+	r.POST("/users/all", namedHandler("static post all"))
+	r.GET("/", namedHandler("root"))
+	r.AddRouteIfNoError(NewRouteRaw(GET, "^/strict$", namedHandler("strict")))
+
+	return r
+}
+
+func routeBodies(t *testing.T, r *Router, requests []events.APIGatewayV2HTTPRequest) []string {
+	bodies := []string{}
+
+	for _, request := range requests {
+		response, err := r.Route(context.Background(), request)
+		if err != nil {
+			bodies = append(bodies, "error: "+err.Error())
+			continue
+		}
+
+		bodies = append(bodies, response.Body)
+	}
+
+	return bodies
+}
+
+func TestRouter_Compile(t *testing.T) {
+	requests := []events.APIGatewayV2HTTPRequest{
+		testRequest(GET, "/users/new"),
+		testRequest(GET, "/users/new/"),
+		testRequest(GET, "/users/all"),
+		testRequest(GET, "/users/all/"),
+		testRequest(GET, "/users/5"),
+		testRequest(GET, "/users/all/extra"),
+		testRequest(POST, "/users/all"),
+		testRequest(POST, "/users/new"),
+		testRequest(GET, "/"),
+		testRequest(GET, "/strict"),
+		testRequest(GET, "/strict/"),
+		testRequest(GET, "/missing"),
+	}
+
+	r := compileTestRouter()
+	expected := routeBodies(t, r, requests)
+
+	r.Compile()
+	assert.NotNil(t, r.matcher)
+	assert.Equal(t, expected, routeBodies(t, r, requests))
+
+	assert.Equal(t, []string{
+		"regex new",
+		"regex new",
+		"static all",
+		"static all",
+		"regex id",
+		"static extra",
+		"static post all",
+		"error: 'POST /users/new' not found",
+		"root",
+		"strict",
+		"error: 'GET /strict/' not found",
+		"error: 'GET /missing' not found",
+	}, expected)
+}
+
+func TestRouter_Compile_invalidated(t *testing.T) {
+	r := compileTestRouter()
+	r.Compile()
+
+	r.GET("/added", namedHandler("added"))
+	assert.Nil(t, r.matcher)
+
+	r.Compile()
+	assert.True(t, r.RemoveRoute(GET, "/users/all"))
+	assert.Nil(t, r.matcher)
+
+	r.Compile()
+	r.Routes = append(r.Routes, r.Routes[0])
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/added"))
+	assert.NoError(t, err)
+	assert.Equal(t, "added", response.Body)
+}
+
+func benchmarkRouter(compile bool) (*Router, events.APIGatewayV2HTTPRequest) {
+	r := &Router{}
+
+	for i := 0; i < 180; i++ {
+		r.GET(fmt.Sprintf("/static/path/%d", i), testHandler)
+	}
+
+	for i := 0; i < 20; i++ {
+		r.GET(fmt.Sprintf("/dynamic/%d/(?P<id>[0-9]+)", i), testHandler)
+	}
+
+	if compile {
+		r.Compile()
+	}
+
+	return r, testRequest(GET, "/static/path/179")
+}
+
+func BenchmarkRouter_Route(b *testing.B) {
+	r, request := benchmarkRouter(false)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, _ = r.Route(context.Background(), request)
+	}
+}
+
+func BenchmarkRouter_Route_compiled(b *testing.B) {
+	r, request := benchmarkRouter(true)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, _ = r.Route(context.Background(), request)
+	}
+}
+
+func TestRouter_Compile_matchedPath(t *testing.T) {
+	r := &Router{}
+	r.GET("/users/all", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return Text(200, ctx.MatchedPath)
+	})
+	r.Compile()
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/users/all/"))
+	assert.NoError(t, err)
+	assert.Equal(t, "/users/all/", response.Body)
+}
@@ -0,0 +1,41 @@
+package proxy
+
+import (
+	"mime"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// RequireContentType returns route middleware that responds with a 415
+// Unsupported Media Type, without calling the handler, when the request's
+// Content-Type doesn't match contentType. The comparison is case insensitive
+// and ignores parameters such as charset.
+//
+// Example:
+//
+//	router.POST("/upload", uploadHandler, RequireContentType("application/json"))
+func RequireContentType(contentType string) Middleware {
+	want := mediaType(contentType)
+
+	return func(next RouteHandler) RouteHandler {
+		return func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+			if mediaType(ctx.Header("content-type")) != want {
+				return Text(415, "unsupported media type")
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// mediaType returns the lowercased media type of the content type value without
+// any parameters, or an empty string if it can't be parsed.
+func mediaType(value string) string {
+	mt, _, err := mime.ParseMediaType(value)
+	if err != nil {
+		return ""
+	}
+
+	return strings.ToLower(mt)
+}
@@ -0,0 +1,70 @@
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMediaType(t *testing.T) {
+	assert.Equal(t, "application/json", mediaType("application/json"))
+	assert.Equal(t, "application/json", mediaType("Application/JSON; charset=utf-8"))
+	assert.Equal(t, "", mediaType(""))
+	assert.Equal(t, "", mediaType(";;"))
+}
+
+func TestRequireContentType(t *testing.T) {
+	called := false
+	handler := func(*RouteContext) (events.APIGatewayProxyResponse, error) {
+		called = true
+		return events.APIGatewayProxyResponse{StatusCode: 200}, nil
+	}
+
+	tests := []struct {
+		contentType string
+		status      int
+		called      bool
+	}{
+		{"application/json", 200, true},
+		{"application/json; charset=utf-8", 200, true},
+		{"APPLICATION/JSON", 200, true},
+		{"text/plain", 415, false},
+		{"", 415, false},
+	}
+
+	for _, test := range tests {
+		called = false
+
+		request := testRequest(POST, "/upload")
+		request.Headers = map[string]string{"content-type": test.contentType}
+
+		response, err := RequireContentType("application/json")(handler)(&RouteContext{Request: request})
+
+		assert.NoError(t, err)
+		assert.Equal(t, test.status, response.StatusCode, test.contentType)
+		assert.Equal(t, test.called, called, test.contentType)
+	}
+}
+
+func TestRouter_RequireContentType(t *testing.T) {
+	r := &Router{}
+	r.POST("/upload", func(*RouteContext) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: 200}, nil
+	}, RequireContentType("application/json"))
+
+	request := testRequest(POST, "/upload")
+	request.Headers = map[string]string{"content-type": "application/json; charset=utf-8"}
+
+	response, err := r.Route(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+
+	request.Headers = map[string]string{"content-type": "text/csv"}
+
+	response, err = r.Route(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, 415, response.StatusCode)
+	assert.Equal(t, "unsupported media type", response.Body)
+}
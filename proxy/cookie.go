@@ -0,0 +1,44 @@
+package proxy
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/pkg/errors"
+)
+
+// SetCookie adds the cookie to the response as a Set-Cookie header. The
+// single valued Headers map can only hold one cookie, so cookies are added to
+// MultiValueHeaders["Set-Cookie"], and a Set-Cookie already in Headers is moved
+// there, so that every cookie is returned. An error is returned if the cookie
+// is invalid, e.g. its name is empty.
+//
+// The function url adapter returns these as the response's Cookies and the alb
+// and http.Handler adapters as repeated headers.
+//
+// Example:
+//
+//	response, err := proxy.Text(200, "signed in")
+//	err = proxy.SetCookie(&response, &http.Cookie{Name: "session", Value: id, HttpOnly: true})
+//	err = proxy.SetCookie(&response, &http.Cookie{Name: "theme", Value: "dark"})
+func SetCookie(response *events.APIGatewayProxyResponse, cookie *http.Cookie) error {
+	if err := cookie.Valid(); err != nil {
+		return errors.Wrapf(err, "invalid cookie '%s'", cookie.Name)
+	}
+
+	if response.MultiValueHeaders == nil {
+		response.MultiValueHeaders = map[string][]string{}
+	}
+
+	for k, v := range response.Headers {
+		if strings.EqualFold(k, "set-cookie") {
+			response.MultiValueHeaders["Set-Cookie"] = append(response.MultiValueHeaders["Set-Cookie"], v)
+			delete(response.Headers, k)
+		}
+	}
+
+	response.MultiValueHeaders["Set-Cookie"] = append(response.MultiValueHeaders["Set-Cookie"], cookie.String())
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package proxy
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetCookie(t *testing.T) {
+	response, _ := Text(200, "signed in")
+
+	assert.NoError(t, SetCookie(&response, &http.Cookie{Name: "session", Value: "abc", HttpOnly: true}))
+	assert.NoError(t, SetCookie(&response, &http.Cookie{Name: "theme", Value: "dark", Path: "/"}))
+
+	assert.Equal(t, []string{"session=abc; HttpOnly", "theme=dark; Path=/"}, response.MultiValueHeaders["Set-Cookie"])
+	assert.Equal(t, "text/plain; charset=utf-8", response.Headers["Content-Type"])
+}
+
+func TestSetCookie_existingHeader(t *testing.T) {
+	response, _ := Text(200, "signed in")
+	response.Headers["set-cookie"] = "legacy=1"
+
+	assert.NoError(t, SetCookie(&response, &http.Cookie{Name: "session", Value: "abc"}))
+
+	assert.Equal(t, []string{"legacy=1", "session=abc"}, response.MultiValueHeaders["Set-Cookie"])
+	assert.NotContains(t, response.Headers, "set-cookie")
+}
+
+func TestSetCookie_invalid(t *testing.T) {
+	response, _ := Text(200, "signed in")
+
+	err := SetCookie(&response, &http.Cookie{Name: "", Value: "abc"})
+
+	assert.Error(t, err)
+	assert.Nil(t, response.MultiValueHeaders)
+}
+
+func TestSetCookie_functionURL(t *testing.T) {
+	response, _ := Text(200, "signed in")
+
+	assert.NoError(t, SetCookie(&response, &http.Cookie{Name: "a", Value: "1"}))
+	assert.NoError(t, SetCookie(&response, &http.Cookie{Name: "b", Value: "2"}))
+
+	fu := ProxyResponseToFunctionURLResponse(response)
+	assert.Equal(t, []string{"a=1", "b=2"}, fu.Cookies)
+}
@@ -0,0 +1,128 @@
+package proxy
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// CORSOptions configures the cross-origin resource sharing headers the router
+// adds to responses when enabled via Router.EnableCORS.
+//
+// AllowedOrigins supports exact matches and '*' for any origin. When
+// AllowedMethods or AllowedHeaders are empty the preflight response echoes the
+// requested method or headers.
+type CORSOptions struct {
+	AllowedOrigins   []string
+	AllowedMethods   []HttpMethod
+	AllowedHeaders   []string
+	AllowCredentials bool
+	MaxAge           int
+}
+
+// allowOrigin returns the value for the Access-Control-Allow-Origin header or
+// an empty string if the origin is not allowed.
+func (opts *CORSOptions) allowOrigin(origin string) string {
+	if origin == "" {
+		return ""
+	}
+
+	for _, allowed := range opts.AllowedOrigins {
+		if allowed == "*" {
+			// '*' is not permitted together with credentials, so echo the origin.
+			if opts.AllowCredentials {
+				return origin
+			}
+
+			return "*"
+		}
+
+		if allowed == origin {
+			return origin
+		}
+	}
+
+	return ""
+}
+
+// isPreflight returns true if the request is a CORS preflight request.
+func (opts *CORSOptions) isPreflight(request events.APIGatewayV2HTTPRequest) bool {
+	return request.RequestContext.HTTP.Method == OPTIONS.String() &&
+		request.Headers["origin"] != "" &&
+		request.Headers["access-control-request-method"] != ""
+}
+
+// headers returns the CORS headers to set on a response for the request.
+func (opts *CORSOptions) headers(request events.APIGatewayV2HTTPRequest) map[string]string {
+	headers := map[string]string{}
+
+	origin := opts.allowOrigin(request.Headers["origin"])
+	if origin == "" {
+		return headers
+	}
+
+	headers["Access-Control-Allow-Origin"] = origin
+
+	if origin != "*" {
+		headers["Vary"] = "Origin"
+	}
+
+	if opts.AllowCredentials {
+		headers["Access-Control-Allow-Credentials"] = "true"
+	}
+
+	return headers
+}
+
+// preflight returns the response to a CORS preflight request.
+func (opts *CORSOptions) preflight(request events.APIGatewayV2HTTPRequest) events.APIGatewayProxyResponse {
+	headers := opts.headers(request)
+
+	if _, ok := headers["Access-Control-Allow-Origin"]; ok {
+		methods := request.Headers["access-control-request-method"]
+		if len(opts.AllowedMethods) > 0 {
+			names := make([]string, 0, len(opts.AllowedMethods))
+			for _, m := range opts.AllowedMethods {
+				names = append(names, m.String())
+			}
+			methods = strings.Join(names, ", ")
+		}
+		headers["Access-Control-Allow-Methods"] = methods
+
+		allowHeaders := request.Headers["access-control-request-headers"]
+		if len(opts.AllowedHeaders) > 0 {
+			allowHeaders = strings.Join(opts.AllowedHeaders, ", ")
+		}
+		if allowHeaders != "" {
+			headers["Access-Control-Allow-Headers"] = allowHeaders
+		}
+
+		if opts.MaxAge > 0 {
+			headers["Access-Control-Max-Age"] = strconv.Itoa(opts.MaxAge)
+		}
+	}
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: 204,
+		Headers:    headers,
+	}
+}
+
+// apply adds the CORS headers for the request to the response.
+func (opts *CORSOptions) apply(request events.APIGatewayV2HTTPRequest, response events.APIGatewayProxyResponse) events.APIGatewayProxyResponse {
+	headers := opts.headers(request)
+	if len(headers) == 0 {
+		return response
+	}
+
+	if response.Headers == nil {
+		response.Headers = map[string]string{}
+	}
+
+	for k, v := range headers {
+		response.Headers[k] = v
+	}
+
+	return response
+}
@@ -0,0 +1,139 @@
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORSOptions_allowOrigin(t *testing.T) {
+	cases := []struct {
+		opts     CORSOptions
+		origin   string
+		expected string
+	}{
+		{CORSOptions{AllowedOrigins: []string{"*"}}, "https://a.com", "*"},
+		{CORSOptions{AllowedOrigins: []string{"*"}, AllowCredentials: true}, "https://a.com", "https://a.com"},
+		{CORSOptions{AllowedOrigins: []string{"https://a.com"}}, "https://a.com", "https://a.com"},
+		{CORSOptions{AllowedOrigins: []string{"https://a.com"}}, "https://b.com", ""},
+		{CORSOptions{AllowedOrigins: []string{"*"}}, "", ""},
+		{CORSOptions{}, "https://a.com", ""},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, c.opts.allowOrigin(c.origin))
+	}
+}
+
+func TestRouter_EnableCORS_preflight(t *testing.T) {
+	r := &Router{}
+	r.GET("/route", testHandler)
+	r.EnableCORS(CORSOptions{
+		AllowedOrigins:   []string{"https://a.com"},
+		AllowedMethods:   []HttpMethod{GET, POST},
+		AllowedHeaders:   []string{"content-type", "authorization"},
+		AllowCredentials: true,
+		MaxAge:           600,
+	})
+
+	request := testRequest(OPTIONS, "/route")
+	request.Headers["origin"] = "https://a.com"
+	request.Headers["access-control-request-method"] = "POST"
+
+	response, err := r.Route(context.Background(), request)
+
+	expected := map[string]string{
+		"Access-Control-Allow-Origin":      "https://a.com",
+		"Access-Control-Allow-Methods":     "GET, POST",
+		"Access-Control-Allow-Headers":     "content-type, authorization",
+		"Access-Control-Allow-Credentials": "true",
+		"Access-Control-Max-Age":           "600",
+		"Vary":                             "Origin",
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, 204, response.StatusCode)
+	assert.Equal(t, expected, response.Headers)
+}
+
+func TestRouter_EnableCORS_preflight_echo(t *testing.T) {
+	r := &Router{}
+	r.EnableCORS(CORSOptions{AllowedOrigins: []string{"*"}})
+
+	request := testRequest(OPTIONS, "/route")
+	request.Headers["origin"] = "https://a.com"
+	request.Headers["access-control-request-method"] = "PUT"
+	request.Headers["access-control-request-headers"] = "x-custom"
+
+	response, err := r.Route(context.Background(), request)
+
+	expected := map[string]string{
+		"Access-Control-Allow-Origin":  "*",
+		"Access-Control-Allow-Methods": "PUT",
+		"Access-Control-Allow-Headers": "x-custom",
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, 204, response.StatusCode)
+	assert.Equal(t, expected, response.Headers)
+}
+
+func TestRouter_EnableCORS_preflight_disallowedOrigin(t *testing.T) {
+	r := &Router{}
+	r.EnableCORS(CORSOptions{AllowedOrigins: []string{"https://a.com"}})
+
+	request := testRequest(OPTIONS, "/route")
+	request.Headers["origin"] = "https://b.com"
+	request.Headers["access-control-request-method"] = "GET"
+
+	response, err := r.Route(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 204, response.StatusCode)
+	assert.Empty(t, response.Headers)
+}
+
+func TestRouter_EnableCORS_inject(t *testing.T) {
+	r := &Router{}
+	r.EnableCORS(CORSOptions{AllowedOrigins: []string{"https://a.com"}})
+
+	routeHandler := func(context *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers:    map[string]string{"Content-Type": "text/plain"},
+		}, nil
+	}
+
+	r.GET("/route", routeHandler)
+
+	request := testRequest(GET, "/route")
+	request.Headers["origin"] = "https://a.com"
+
+	response, err := r.Route(context.Background(), request)
+
+	expected := map[string]string{
+		"Content-Type":                "text/plain",
+		"Access-Control-Allow-Origin": "https://a.com",
+		"Vary":                        "Origin",
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, expected, response.Headers)
+}
+
+func TestRouter_EnableCORS_inject_nilHeaders(t *testing.T) {
+	r := &Router{}
+	r.EnableCORS(CORSOptions{AllowedOrigins: []string{"*"}})
+	r.GET("/route", testHandler)
+
+	request := testRequest(GET, "/route")
+	request.Headers["origin"] = "https://a.com"
+
+	response, err := r.Route(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Access-Control-Allow-Origin": "*"}, response.Headers)
+}
@@ -0,0 +1,55 @@
+package proxy
+
+import (
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// SetDefaultResponseHeaders sets headers, such as security headers, that are
+// merged into every response the router returns, including CatchAll, not found,
+// error and CORS preflight responses. A header set on the response, compared
+// case insensitively, takes precedence over the default.
+func (router *Router) SetDefaultResponseHeaders(headers map[string]string) {
+	router.defaultHeaders = make(map[string]string, len(headers))
+
+	for k, v := range headers {
+		router.defaultHeaders[k] = v
+	}
+}
+
+// applyDefaultHeaders adds the default headers missing from the response. The
+// response headers are copied so a map shared between responses isn't
+// modified.
+func (router *Router) applyDefaultHeaders(response events.APIGatewayProxyResponse) events.APIGatewayProxyResponse {
+	if len(router.defaultHeaders) == 0 {
+		return response
+	}
+
+	headers := make(map[string]string, len(response.Headers)+len(router.defaultHeaders))
+	for k, v := range response.Headers {
+		headers[k] = v
+	}
+
+	for k, v := range router.defaultHeaders {
+		if !hasHeader(headers, k) {
+			headers[k] = v
+		}
+	}
+
+	response.Headers = headers
+
+	return response
+}
+
+// hasHeader returns true if the named header is set, compared case
+// insensitively.
+func hasHeader(headers map[string]string, name string) bool {
+	for k := range headers {
+		if strings.EqualFold(k, name) {
+			return true
+		}
+	}
+
+	return false
+}
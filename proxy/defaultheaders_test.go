@@ -0,0 +1,72 @@
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter_SetDefaultResponseHeaders(t *testing.T) {
+	shared := map[string]string{"x-content-type-options": "handler"}
+
+	r := &Router{}
+	r.SetDefaultResponseHeaders(map[string]string{
+		"X-Content-Type-Options":    "nosniff",
+		"Strict-Transport-Security": "max-age=63072000",
+	})
+	r.GET("/default", testHandler)
+	r.GET("/override", func(*RouteContext) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: 200, Headers: shared}, nil
+	})
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/default"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"X-Content-Type-Options":    "nosniff",
+		"Strict-Transport-Security": "max-age=63072000",
+	}, response.Headers)
+
+	response, err = r.Route(context.Background(), testRequest(GET, "/override"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"x-content-type-options":    "handler",
+		"Strict-Transport-Security": "max-age=63072000",
+	}, response.Headers)
+	assert.Equal(t, map[string]string{"x-content-type-options": "handler"}, shared)
+}
+
+func TestRouter_SetDefaultResponseHeaders_catchAllAndErrors(t *testing.T) {
+	r := &Router{}
+	r.SetDefaultResponseHeaders(map[string]string{"X-Content-Type-Options": "nosniff"})
+	r.AddCatchAllHandler(func(context.Context, events.APIGatewayV2HTTPRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: 404}, nil
+	})
+	r.AddErrorHandler(func(ctx context.Context, request events.APIGatewayV2HTTPRequest, err error) (events.APIGatewayProxyResponse, error) {
+		return Text(500, err.Error())
+	})
+	r.GET("/boom", func(*RouteContext) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{}, HTTPError{Status: 400, Message: "boom"}
+	})
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/missing"))
+	assert.NoError(t, err)
+	assert.Equal(t, 404, response.StatusCode)
+	assert.Equal(t, "nosniff", response.Headers["X-Content-Type-Options"])
+
+	response, err = r.Route(context.Background(), testRequest(GET, "/boom"))
+	assert.NoError(t, err)
+	assert.Equal(t, 500, response.StatusCode)
+	assert.Equal(t, "nosniff", response.Headers["X-Content-Type-Options"])
+	assert.Equal(t, "text/plain; charset=utf-8", response.Headers["Content-Type"])
+}
+
+func TestRouter_SetDefaultResponseHeaders_unset(t *testing.T) {
+	r := &Router{}
+	r.GET("/default", testHandler)
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/default"))
+	assert.NoError(t, err)
+	assert.Nil(t, response.Headers)
+}
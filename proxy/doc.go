@@ -3,6 +3,11 @@
 // routing functionality and processing the entire request/response through the
 // lambda via events.APIGatewayV2HTTPRequest and events.APIGatewayProxyResponse.
 //
+// Api gateway v1 (rest) requests are supported by converting them to the v2
+// shape, see V1ToV2Request and Router.RouteV1. Likewise lambda function url
+// requests are supported via Router.RouteFunctionURL and alb target group
+// requests via Router.RouteALB.
+//
 // The router is designed to be as simplistic as possible and is not feature
 // rich.
 package proxy
@@ -0,0 +1,105 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// emfUnmatchedRoute is the Route dimension of requests that matched no route.
+const emfUnmatchedRoute = "unmatched"
+
+// EnableEMFMetrics configures the router to write a cloudwatch embedded metric
+// format (EMF) json line to stdout for every request it routes. The metrics
+// Count and LatencyMs are written to the namespace with the dimensions Route and
+// Method. Route is the name of the matched route, or its pattern when unnamed,
+// and 'unmatched' when no route matched.
+//
+// Lambda forwards stdout to cloudwatch logs, which extracts the metrics without
+// the need for a metrics sdk. By default no metrics are written.
+func (router *Router) EnableEMFMetrics(namespace string) {
+	router.emfNamespace = namespace
+}
+
+// emfLog is a single EMF log line.
+type emfLog struct {
+	AWS       emfMetadata `json:"_aws"`
+	Route     string      `json:"Route"`
+	Method    string      `json:"Method"`
+	Count     int         `json:"Count"`
+	LatencyMs float64     `json:"LatencyMs"`
+}
+
+// emfMetadata is the '_aws' metadata of an EMF log line.
+type emfMetadata struct {
+	Timestamp         int64                `json:"Timestamp"`
+	CloudWatchMetrics []emfMetricDirective `json:"CloudWatchMetrics"`
+}
+
+// emfMetricDirective describes the metrics of an EMF log line.
+type emfMetricDirective struct {
+	Namespace  string      `json:"Namespace"`
+	Dimensions [][]string  `json:"Dimensions"`
+	Metrics    []emfMetric `json:"Metrics"`
+}
+
+// emfMetric is the name and unit of a metric.
+type emfMetric struct {
+	Name string `json:"Name"`
+	Unit string `json:"Unit"`
+}
+
+// emfOutput returns the writer EMF lines are written to, stdout by default.
+func (router *Router) emfOutput() io.Writer {
+	if router.emfWriter != nil {
+		return router.emfWriter
+	}
+
+	return os.Stdout
+}
+
+// emitMetrics writes the EMF line for the request if EMF metrics are enabled.
+func (router *Router) emitMetrics(start time.Time, request events.APIGatewayV2HTTPRequest, route *Route) {
+	if router.emfNamespace == "" {
+		return
+	}
+
+	end := router.now()
+
+	name := emfUnmatchedRoute
+	if route != nil {
+		name = route.Name
+		if name == "" {
+			name = route.Regex.String()
+		}
+	}
+
+	line := emfLog{
+		AWS: emfMetadata{
+			Timestamp: end.UnixMilli(),
+			CloudWatchMetrics: []emfMetricDirective{{
+				Namespace:  router.emfNamespace,
+				Dimensions: [][]string{{"Route", "Method"}},
+				Metrics: []emfMetric{
+					{Name: "Count", Unit: "Count"},
+					{Name: "LatencyMs", Unit: "Milliseconds"},
+				},
+			}},
+		},
+		Route:     name,
+		Method:    request.RequestContext.HTTP.Method,
+		Count:     1,
+		LatencyMs: float64(end.Sub(start)) / float64(time.Millisecond),
+	}
+
+	b, err := json.Marshal(line)
+	if err != nil {
+		return
+	}
+
+	fmt.Fprintln(router.emfOutput(), string(b))
+}
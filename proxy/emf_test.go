@@ -0,0 +1,88 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter_EnableEMFMetrics(t *testing.T) {
+	r := &Router{}
+	r.EnableEMFMetrics("yolo-service")
+	r.nowFunc = stepClock(5 * time.Millisecond)
+	r.GETNamed("user", "/users/:id", testHandler)
+
+	stdout := os.Stdout
+	reader, writer, err := os.Pipe()
+	assert.NoError(t, err)
+	os.Stdout = writer
+
+	_, err = r.Route(context.Background(), testRequest(GET, "/users/42"))
+
+	os.Stdout = stdout
+	assert.NoError(t, writer.Close())
+	assert.NoError(t, err)
+
+	out, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+
+	var line map[string]interface{}
+	assert.NoError(t, json.Unmarshal(out, &line))
+
+	assert.Equal(t, "user", line["Route"])
+	assert.Equal(t, "GET", line["Method"])
+	assert.Equal(t, float64(1), line["Count"])
+	assert.Equal(t, float64(5), line["LatencyMs"])
+
+	metadata := line["_aws"].(map[string]interface{})
+	assert.NotZero(t, metadata["Timestamp"])
+
+	directive := metadata["CloudWatchMetrics"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "yolo-service", directive["Namespace"])
+	assert.Equal(t, []interface{}{[]interface{}{"Route", "Method"}}, directive["Dimensions"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"Name": "Count", "Unit": "Count"},
+		map[string]interface{}{"Name": "LatencyMs", "Unit": "Milliseconds"},
+	}, directive["Metrics"])
+}
+
+func TestRouter_EnableEMFMetrics_routeDimension(t *testing.T) {
+	var buf bytes.Buffer
+
+	r := &Router{}
+	r.EnableEMFMetrics("yolo-service")
+	r.emfWriter = &buf
+	r.GET("/users", testHandler)
+
+	_, _ = r.Route(context.Background(), testRequest(GET, "/users"))
+	_, _ = r.Route(context.Background(), testRequest(POST, "/missing"))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+
+	var matched, unmatched emfLog
+	assert.NoError(t, json.Unmarshal(lines[0], &matched))
+	assert.NoError(t, json.Unmarshal(lines[1], &unmatched))
+
+	assert.Equal(t, "^/users/?$", matched.Route)
+	assert.Equal(t, "unmatched", unmatched.Route)
+	assert.Equal(t, "POST", unmatched.Method)
+}
+
+func TestRouter_EMFMetrics_disabled(t *testing.T) {
+	var buf bytes.Buffer
+
+	r := &Router{}
+	r.emfWriter = &buf
+	r.GET("/users", testHandler)
+
+	_, _ = r.Route(context.Background(), testRequest(GET, "/users"))
+
+	assert.Equal(t, "", buf.String())
+}
@@ -0,0 +1,62 @@
+package proxy
+
+import (
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// MatchesETag returns true if the request's If-None-Match header matches the
+// given etag, in which case the handler can respond with NotModified. The
+// comparison is weak, as required for If-None-Match, so 'W/"v1"' matches
+// '"v1"'. Any of multiple comma separated values may match and '*' matches any
+// etag. The etag may be given with or without its surrounding quotes.
+//
+// Example:
+//
+//	etag := computeETag(report)
+//	if ctx.MatchesETag(etag) {
+//		return proxy.NotModified(etag)
+//	}
+func (ctx *RouteContext) MatchesETag(etag string) bool {
+	if etag == "" {
+		return false
+	}
+
+	opaque := opaqueTag(etag)
+
+	for _, value := range ctx.Headers("if-none-match") {
+		if value == "*" || (value != "" && opaqueTag(value) == opaque) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NotModified returns a 304 response with the ETag header set to the given
+// etag, quoted if it isn't already, and an empty body.
+func NotModified(etag string) (events.APIGatewayProxyResponse, error) {
+	response := events.APIGatewayProxyResponse{
+		StatusCode: 304,
+		Headers:    map[string]string{"ETag": quoteETag(etag)},
+	}
+
+	return response, nil
+}
+
+// opaqueTag returns the etag without its weak 'W/' prefix and quotes.
+func opaqueTag(etag string) string {
+	etag = strings.TrimPrefix(etag, "W/")
+	return strings.Trim(etag, `"`)
+}
+
+// quoteETag returns the etag quoted, leaving an already quoted or weak etag as
+// is.
+func quoteETag(etag string) string {
+	if strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+
+	return `"` + etag + `"`
+}
@@ -0,0 +1,50 @@
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func etagContext(ifNoneMatch string) *RouteContext {
+	request := testRequest(GET, "/report")
+	if ifNoneMatch != "" {
+		request.Headers["if-none-match"] = ifNoneMatch
+	}
+
+	return newRouteContext(context.Background(), request, map[string]string{})
+}
+
+func TestRouteContext_MatchesETag(t *testing.T) {
+	assert.True(t, etagContext(`"v1"`).MatchesETag(`"v1"`))
+	assert.True(t, etagContext(`"v1"`).MatchesETag("v1"))
+	assert.True(t, etagContext(`"v0", "v1"`).MatchesETag(`"v1"`))
+	assert.True(t, etagContext("*").MatchesETag(`"v1"`))
+}
+
+func TestRouteContext_MatchesETag_noMatch(t *testing.T) {
+	assert.False(t, etagContext(`"v0"`).MatchesETag(`"v1"`))
+	assert.False(t, etagContext(`"v0", "v2"`).MatchesETag(`"v1"`))
+	assert.False(t, etagContext("").MatchesETag(`"v1"`))
+	assert.False(t, etagContext(`"v1"`).MatchesETag(""))
+}
+
+func TestRouteContext_MatchesETag_weak(t *testing.T) {
+	assert.True(t, etagContext(`W/"v1"`).MatchesETag(`"v1"`))
+	assert.True(t, etagContext(`"v1"`).MatchesETag(`W/"v1"`))
+	assert.True(t, etagContext(`W/"v0", W/"v1"`).MatchesETag(`W/"v1"`))
+	assert.False(t, etagContext(`W/"v0"`).MatchesETag(`"v1"`))
+}
+
+func TestNotModified(t *testing.T) {
+	response, err := NotModified("v1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 304, response.StatusCode)
+	assert.Equal(t, `"v1"`, response.Headers["ETag"])
+	assert.Empty(t, response.Body)
+
+	response, _ = NotModified(`W/"v1"`)
+	assert.Equal(t, `W/"v1"`, response.Headers["ETag"])
+}
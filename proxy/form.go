@@ -0,0 +1,78 @@
+package proxy
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// maxFormMemory is the maximum number of bytes of a multipart form kept in
+// memory, see multipart.Reader.ReadForm.
+const maxFormMemory = 32 << 20
+
+// Form returns the values of a urlencoded or multipart form body, base64
+// decoding it if required. Unlike Params repeated keys keep all their values.
+// Only the values of a multipart form are returned, not its files.
+func (ctx *RouteContext) Form() (url.Values, error) {
+	mt, params, err := mime.ParseMediaType(ctx.Header("content-type"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed parsing form content type")
+	}
+
+	body, err := ctx.BodyBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(mt) {
+	case "application/x-www-form-urlencoded":
+		return parseURLEncodedForm(string(body))
+	case "multipart/form-data":
+		form, err := multipart.NewReader(bytes.NewReader(body), params["boundary"]).ReadForm(maxFormMemory)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed parsing multipart form")
+		}
+		defer form.RemoveAll()
+
+		return url.Values(form.Value), nil
+	}
+
+	return nil, fmt.Errorf("unsupported form content type '%s'", mt)
+}
+
+// parseURLEncodedForm parses the urlencoded form body. Values may contain '='
+// and both keys and values are query unescaped.
+func parseURLEncodedForm(body string) (url.Values, error) {
+	values := url.Values{}
+
+	if body == "" {
+		return values, nil
+	}
+
+	for _, kv := range strings.Split(body, "&") {
+		kvSplit := strings.SplitN(kv, "=", 2)
+
+		if len(kvSplit) != 2 {
+			return nil, fmt.Errorf("invalid key/value pair '%v' in form post", kv)
+		}
+
+		k, err := url.QueryUnescape(kvSplit[0])
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to decode key '%v'", kvSplit[0])
+		}
+
+		v, err := url.QueryUnescape(kvSplit[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to decode value '%v'", kvSplit[1])
+		}
+
+		values.Add(k, v)
+	}
+
+	return values, nil
+}
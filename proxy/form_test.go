@@ -0,0 +1,98 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/base64"
+	"mime/multipart"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func formRequestContext(contentType string, body string) *RouteContext {
+	request := testRequest(POST, "/form")
+	request.Headers["content-type"] = contentType
+	request.Body = body
+
+	return &RouteContext{Request: request}
+}
+
+func TestParseURLEncodedForm(t *testing.T) {
+	values, err := parseURLEncodedForm("tag=a&tag=b&token=abc==&first+name=jeff&empty=")
+	assert.NoError(t, err)
+
+	expected := url.Values{
+		"tag":        {"a", "b"},
+		"token":      {"abc=="},
+		"first name": {"jeff"},
+		"empty":      {""},
+	}
+
+	assert.Equal(t, expected, values)
+}
+
+func TestParseURLEncodedForm_empty(t *testing.T) {
+	values, err := parseURLEncodedForm("")
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{}, values)
+}
+
+func TestParseURLEncodedForm_error(t *testing.T) {
+	_, err := parseURLEncodedForm("asdfg=qrr&sas")
+	assert.EqualError(t, err, "invalid key/value pair 'sas' in form post")
+
+	_, err = parseURLEncodedForm("as%Zdfg=hi")
+	assert.Contains(t, err.Error(), "unable to decode key")
+
+	_, err = parseURLEncodedForm("asdfg=hi %Z yolo")
+	assert.Contains(t, err.Error(), "unable to decode value")
+}
+
+func TestRouteContext_Form(t *testing.T) {
+	ctx := formRequestContext("application/x-www-form-urlencoded; charset=utf-8", "tag=a&tag=b&eq=a=b")
+
+	values, err := ctx.Form()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, values["tag"])
+	assert.Equal(t, "a", values.Get("tag"))
+	assert.Equal(t, "a=b", values.Get("eq"))
+}
+
+func TestRouteContext_Form_base64(t *testing.T) {
+	ctx := formRequestContext("application/x-www-form-urlencoded", base64.StdEncoding.EncodeToString([]byte("tag=a&tag=b")))
+	ctx.Request.IsBase64Encoded = true
+
+	values, err := ctx.Form()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, values["tag"])
+}
+
+func TestRouteContext_Form_multipart(t *testing.T) {
+	var buf bytes.Buffer
+
+	w := multipart.NewWriter(&buf)
+	assert.NoError(t, w.WriteField("tag", "a"))
+	assert.NoError(t, w.WriteField("tag", "b"))
+	assert.NoError(t, w.WriteField("name", "yolo"))
+	assert.NoError(t, w.Close())
+
+	ctx := formRequestContext(w.FormDataContentType(), base64.StdEncoding.EncodeToString(buf.Bytes()))
+	ctx.Request.IsBase64Encoded = true
+
+	values, err := ctx.Form()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, values["tag"])
+	assert.Equal(t, "yolo", values.Get("name"))
+}
+
+func TestRouteContext_Form_error(t *testing.T) {
+	_, err := formRequestContext("application/json", "{}").Form()
+	assert.EqualError(t, err, "unsupported form content type 'application/json'")
+
+	_, err = formRequestContext("", "tag=a").Form()
+	assert.Error(t, err)
+
+	_, err = formRequestContext("application/x-www-form-urlencoded", "tag").Form()
+	assert.Error(t, err)
+}
@@ -0,0 +1,84 @@
+package proxy
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// FunctionURLToV2Request converts a lambda function url request into the api
+// gateway v2 (http) request shape used by the router.
+func FunctionURLToV2Request(request events.LambdaFunctionURLRequest) events.APIGatewayV2HTTPRequest {
+	rc := request.RequestContext
+
+	return events.APIGatewayV2HTTPRequest{
+		Version:               request.Version,
+		RawPath:               request.RawPath,
+		RawQueryString:        request.RawQueryString,
+		Cookies:               request.Cookies,
+		Headers:               request.Headers,
+		QueryStringParameters: request.QueryStringParameters,
+		Body:                  request.Body,
+		IsBase64Encoded:       request.IsBase64Encoded,
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			AccountID:    rc.AccountID,
+			RequestID:    rc.RequestID,
+			APIID:        rc.APIID,
+			DomainName:   rc.DomainName,
+			DomainPrefix: rc.DomainPrefix,
+			Time:         rc.Time,
+			TimeEpoch:    rc.TimeEpoch,
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method:    rc.HTTP.Method,
+				Path:      rc.HTTP.Path,
+				Protocol:  rc.HTTP.Protocol,
+				SourceIP:  rc.HTTP.SourceIP,
+				UserAgent: rc.HTTP.UserAgent,
+			},
+		},
+	}
+}
+
+// ProxyResponseToFunctionURLResponse converts a router response into a lambda
+// function url response. Multi-value headers are comma joined, except for
+// Set-Cookie which is moved into the response cookies.
+func ProxyResponseToFunctionURLResponse(response events.APIGatewayProxyResponse) events.LambdaFunctionURLResponse {
+	headers := map[string]string{}
+	cookies := []string{}
+
+	for k, v := range response.Headers {
+		if strings.EqualFold(k, "set-cookie") {
+			cookies = append(cookies, v)
+			continue
+		}
+
+		headers[k] = v
+	}
+
+	for k, v := range response.MultiValueHeaders {
+		if strings.EqualFold(k, "set-cookie") {
+			cookies = append(cookies, v...)
+			continue
+		}
+
+		headers[k] = strings.Join(v, ",")
+	}
+
+	return events.LambdaFunctionURLResponse{
+		StatusCode:      response.StatusCode,
+		Headers:         headers,
+		Body:            response.Body,
+		IsBase64Encoded: response.IsBase64Encoded,
+		Cookies:         cookies,
+	}
+}
+
+// RouteFunctionURL routes a lambda function url request by converting it with
+// FunctionURLToV2Request and passing it to Route. The response is converted
+// with ProxyResponseToFunctionURLResponse.
+func (router *Router) RouteFunctionURL(ctx context.Context, request events.LambdaFunctionURLRequest) (events.LambdaFunctionURLResponse, error) {
+	response, err := router.Route(ctx, FunctionURLToV2Request(request))
+
+	return ProxyResponseToFunctionURLResponse(response), err
+}
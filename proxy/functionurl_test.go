@@ -0,0 +1,73 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionURLToV2Request(t *testing.T) {
+	request := FunctionURLToV2Request(dummyLambdaFunctionURLRequest("function-url"))
+
+	assert.Equal(t, "/wowza/42", request.RawPath)
+	assert.Equal(t, "jones=arm", request.RawQueryString)
+	assert.Equal(t, "GET", request.RequestContext.HTTP.Method)
+	assert.Equal(t, "1.1.1.1", request.RequestContext.HTTP.SourceIP)
+	assert.Equal(t, "id", request.RequestContext.RequestID)
+	assert.Equal(t, []string{"session=abc"}, request.Cookies)
+	assert.Equal(t, map[string]string{"jones": "arm"}, request.QueryStringParameters)
+	assert.Equal(t, "application/json", request.Headers["accept"])
+}
+
+func TestProxyResponseToFunctionURLResponse(t *testing.T) {
+	response := ProxyResponseToFunctionURLResponse(events.APIGatewayProxyResponse{
+		StatusCode:        201,
+		Headers:           map[string]string{"Content-Type": "text/plain", "Set-Cookie": "a=1"},
+		MultiValueHeaders: map[string][]string{"X-Multi": {"one", "two"}, "Set-Cookie": {"b=2", "c=3"}},
+		Body:              "aGk=",
+		IsBase64Encoded:   true,
+	})
+
+	assert.Equal(t, 201, response.StatusCode)
+	assert.Equal(t, map[string]string{"Content-Type": "text/plain", "X-Multi": "one,two"}, response.Headers)
+	assert.Equal(t, []string{"a=1", "b=2", "c=3"}, response.Cookies)
+	assert.Equal(t, "aGk=", response.Body)
+	assert.True(t, response.IsBase64Encoded)
+}
+
+func TestRouter_RouteFunctionURL(t *testing.T) {
+	r := &Router{}
+
+	routeHandler := func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Body:       ctx.Params["id"] + " " + ctx.Params["jones"],
+		}, nil
+	}
+
+	r.GET("/wowza/(?P<id>[0-9]+)", routeHandler)
+
+	response, err := r.RouteFunctionURL(context.Background(), dummyLambdaFunctionURLRequest("function-url"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, "42 arm", response.Body)
+}
+
+func TestRouter_RouteFunctionURL_error(t *testing.T) {
+	r := &Router{}
+
+	routeHandler := func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: 500}, errors.New("failed")
+	}
+
+	r.GET("/wowza/(?P<id>[0-9]+)", routeHandler)
+
+	response, err := r.RouteFunctionURL(context.Background(), dummyLambdaFunctionURLRequest("function-url"))
+
+	assert.Error(t, err)
+	assert.Equal(t, 500, response.StatusCode)
+}
@@ -0,0 +1,125 @@
+package proxy
+
+import (
+	"regexp"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// RouteGroup adds routes to a router with a shared path prefix and shared
+// middleware. The prefix is treated as a literal and is prepended to each route
+// pattern before it is compiled.
+//
+// Group middleware wraps the handlers of the group's routes, and those of any
+// nested groups, running after the router middleware and before the route
+// middleware.
+//
+// Example:
+//
+//	api := router.Group("/api/v1")
+//	api.Use(authMiddleware)
+//	api.GET("/users/(?P<id>[0-9]+)", userHandler)
+type RouteGroup struct {
+	router     *Router
+	parent     *RouteGroup
+	prefix     string
+	middleware []Middleware
+}
+
+// Group returns a new RouteGroup whose routes have the prefix prepended.
+func (router *Router) Group(prefix string) *RouteGroup {
+	return &RouteGroup{router: router, prefix: prefix}
+}
+
+// Group returns a new nested RouteGroup whose routes have both this group's
+// prefix and the given prefix prepended.
+func (group *RouteGroup) Group(prefix string) *RouteGroup {
+	return &RouteGroup{router: group.router, parent: group, prefix: group.prefix + prefix}
+}
+
+// Use appends middleware that wraps the handler of every route in the group,
+// including nested groups. The middleware runs in the order it was added.
+func (group *RouteGroup) Use(middleware ...Middleware) {
+	group.middleware = append(group.middleware, middleware...)
+}
+
+// allMiddleware returns the middleware of this group, preceded by that of any
+// parent groups.
+func (group *RouteGroup) allMiddleware() []Middleware {
+	if group.parent == nil {
+		return group.middleware
+	}
+
+	return append(append([]Middleware{}, group.parent.allMiddleware()...), group.middleware...)
+}
+
+// wrap is the route middleware that applies the group middleware. It is
+// resolved at request time so middleware added after a route still applies.
+func (group *RouteGroup) wrap(next RouteHandler) RouteHandler {
+	return func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return chain(next, group.allMiddleware()...)(ctx)
+	}
+}
+
+// add adds a new route to the router with the group prefix prepended to the
+// pattern.
+func (group *RouteGroup) add(method HttpMethod, match string, handler RouteHandler, middleware ...Middleware) {
+	pattern := regexp.QuoteMeta(group.prefix) + match
+	middleware = append([]Middleware{group.wrap}, middleware...)
+
+	group.router.AddRouteIfNoError(group.router.compileRoute(method, pattern, handler, middleware...))
+}
+
+// GET adds a new GET route with the group prefix prepended to the specified
+// pattern match, handler and optional route middleware.
+func (group *RouteGroup) GET(match string, handler RouteHandler, middleware ...Middleware) {
+	group.add(GET, match, handler, middleware...)
+}
+
+// HEAD adds a new HEAD route with the group prefix prepended to the specified
+// pattern match, handler and optional route middleware.
+func (group *RouteGroup) HEAD(match string, handler RouteHandler, middleware ...Middleware) {
+	group.add(HEAD, match, handler, middleware...)
+}
+
+// POST adds a new POST route with the group prefix prepended to the specified
+// pattern match, handler and optional route middleware.
+func (group *RouteGroup) POST(match string, handler RouteHandler, middleware ...Middleware) {
+	group.add(POST, match, handler, middleware...)
+}
+
+// PUT adds a new PUT route with the group prefix prepended to the specified
+// pattern match, handler and optional route middleware.
+func (group *RouteGroup) PUT(match string, handler RouteHandler, middleware ...Middleware) {
+	group.add(PUT, match, handler, middleware...)
+}
+
+// DELETE adds a new DELETE route with the group prefix prepended to the specified
+// pattern match, handler and optional route middleware.
+func (group *RouteGroup) DELETE(match string, handler RouteHandler, middleware ...Middleware) {
+	group.add(DELETE, match, handler, middleware...)
+}
+
+// CONNECT adds a new CONNECT route with the group prefix prepended to the specified
+// pattern match, handler and optional route middleware.
+func (group *RouteGroup) CONNECT(match string, handler RouteHandler, middleware ...Middleware) {
+	group.add(CONNECT, match, handler, middleware...)
+}
+
+// OPTIONS adds a new OPTIONS route with the group prefix prepended to the specified
+// pattern match, handler and optional route middleware.
+func (group *RouteGroup) OPTIONS(match string, handler RouteHandler, middleware ...Middleware) {
+	group.add(OPTIONS, match, handler, middleware...)
+}
+
+// TRACE adds a new TRACE route with the group prefix prepended to the specified
+// pattern match, handler and optional route middleware.
+func (group *RouteGroup) TRACE(match string, handler RouteHandler, middleware ...Middleware) {
+	group.add(TRACE, match, handler, middleware...)
+}
+
+// PATCH adds a new PATCH route with the group prefix prepended to the specified
+// pattern match, handler and optional route middleware.
+func (group *RouteGroup) PATCH(match string, handler RouteHandler, middleware ...Middleware) {
+	group.add(PATCH, match, handler, middleware...)
+}
@@ -0,0 +1,99 @@
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter_Group(t *testing.T) {
+	r := &Router{}
+
+	api := r.Group("/api/v1")
+	api.GET("/users/(?P<id>[0-9]+)", testHandler)
+	api.POST("/users", testHandler)
+
+	assert.True(t, r.Valid())
+	assert.Len(t, r.Routes, 2)
+	assert.Equal(t, `GET ^/api/v1/users/(?P<id>[0-9]+)/?$`, r.Routes[0].String())
+	assert.Equal(t, `POST ^/api/v1/users/?$`, r.Routes[1].String())
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/api/v1/users/42"))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+
+	_, err = r.Route(context.Background(), testRequest(GET, "/users/42"))
+	assert.Error(t, err)
+}
+
+func TestRouter_Group_literalPrefix(t *testing.T) {
+	r := &Router{}
+
+	r.Group("/v1.0").GET("/yolo", testHandler)
+
+	assert.True(t, r.Valid())
+	assert.Equal(t, `GET ^/v1\.0/yolo/?$`, r.Routes[0].String())
+
+	_, err := r.Route(context.Background(), testRequest(GET, "/v1x0/yolo"))
+	assert.Error(t, err)
+}
+
+func TestRouter_Group_convenienceMethods(t *testing.T) {
+	r := &Router{}
+	g := r.Group("/g")
+	g.GET("/route", testHandler)
+	g.HEAD("/route", testHandler)
+	g.POST("/route", testHandler)
+	g.PUT("/route", testHandler)
+	g.DELETE("/route", testHandler)
+	g.CONNECT("/route", testHandler)
+	g.OPTIONS("/route", testHandler)
+	g.TRACE("/route", testHandler)
+	g.PATCH("/route", testHandler)
+
+	assert.Len(t, r.Routes, 9)
+	for i, m := range []HttpMethod{GET, HEAD, POST, PUT, DELETE, CONNECT, OPTIONS, TRACE, PATCH} {
+		assert.Equal(t, m.String()+" ^/g/route/?$", r.Routes[i].String())
+	}
+}
+
+func TestRouter_Group_nested(t *testing.T) {
+	r := &Router{}
+	calls := []string{}
+
+	routeHandler := func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		calls = append(calls, "handler")
+		return events.APIGatewayProxyResponse{StatusCode: 200, Body: ctx.Params["id"]}, nil
+	}
+
+	r.Use(appendMiddleware("router", &calls))
+
+	api := r.Group("/api")
+	api.Use(appendMiddleware("api", &calls))
+	api.GET("/ping", routeHandler)
+
+	v1 := api.Group("/v1")
+	v1.GET("/users/(?P<id>[0-9]+)", routeHandler, appendMiddleware("route", &calls))
+	v1.Use(appendMiddleware("v1", &calls))
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/api/v1/users/42"))
+	assert.NoError(t, err)
+	assert.Equal(t, "42", response.Body)
+	assert.Equal(t, []string{"router", "api", "v1", "route", "handler"}, calls)
+
+	calls = []string{}
+	_, err = r.Route(context.Background(), testRequest(GET, "/api/ping"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"router", "api", "handler"}, calls)
+}
+
+func TestRouter_Group_error(t *testing.T) {
+	r := &Router{}
+
+	r.Group("/api").GET("/(?P<bad", testHandler)
+
+	assert.False(t, r.Valid())
+	assert.Empty(t, r.Routes)
+}
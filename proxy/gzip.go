@@ -0,0 +1,75 @@
+package proxy
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/pkg/errors"
+)
+
+// GzipResponses returns middleware that gzip compresses response bodies larger
+// than minBytes when the request's Accept-Encoding header includes gzip. The
+// compressed body is base64 encoded and Content-Encoding is set to gzip.
+//
+// Responses that are already base64 encoded or that already have a
+// Content-Encoding header are left as is.
+func GzipResponses(minBytes int) Middleware {
+	return func(next RouteHandler) RouteHandler {
+		return func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+			response, err := next(ctx)
+			if err != nil {
+				return response, err
+			}
+
+			if !acceptsGzip(ctx) || response.IsBase64Encoded || len(response.Body) <= minBytes {
+				return response, nil
+			}
+
+			for k := range response.Headers {
+				if strings.EqualFold(k, "content-encoding") {
+					return response, nil
+				}
+			}
+
+			var buf bytes.Buffer
+
+			zw := gzip.NewWriter(&buf)
+			if _, err := zw.Write([]byte(response.Body)); err != nil {
+				return response, errors.Wrap(err, "failed gzip compressing response")
+			}
+
+			if err := zw.Close(); err != nil {
+				return response, errors.Wrap(err, "failed gzip compressing response")
+			}
+
+			// the handler's headers may be shared, so add to a copy
+			headers := make(map[string]string, len(response.Headers)+1)
+			for k, v := range response.Headers {
+				headers[k] = v
+			}
+
+			headers["Content-Encoding"] = "gzip"
+			response.Headers = headers
+			response.Body = base64.StdEncoding.EncodeToString(buf.Bytes())
+			response.IsBase64Encoded = true
+
+			return response, nil
+		}
+	}
+}
+
+// acceptsGzip returns true if the request's Accept-Encoding header includes
+// gzip.
+func acceptsGzip(ctx *RouteContext) bool {
+	for _, encoding := range ctx.Headers("accept-encoding") {
+		name := strings.TrimSpace(strings.SplitN(encoding, ";", 2)[0])
+		if strings.EqualFold(name, "gzip") {
+			return true
+		}
+	}
+
+	return false
+}
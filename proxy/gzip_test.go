@@ -0,0 +1,103 @@
+package proxy
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func gzipContext(acceptEncoding string) *RouteContext {
+	request := testRequest(GET, "/yolo")
+	if acceptEncoding != "" {
+		request.Headers["accept-encoding"] = acceptEncoding
+	}
+
+	return &RouteContext{Request: request}
+}
+
+func gzipHandler(response events.APIGatewayProxyResponse) RouteHandler {
+	return func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return response, nil
+	}
+}
+
+func TestGzipResponses(t *testing.T) {
+	body := strings.Repeat(`{"yolo": "it's true"}`, 100)
+	handler := GzipResponses(1024)(gzipHandler(events.APIGatewayProxyResponse{
+		StatusCode: 200,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       body,
+	}))
+
+	response, err := handler(gzipContext("deflate, gzip;q=1.0, br"))
+
+	assert.NoError(t, err)
+	assert.True(t, response.IsBase64Encoded)
+	assert.Equal(t, "gzip", response.Headers["Content-Encoding"])
+	assert.Equal(t, "application/json", response.Headers["Content-Type"])
+
+	b, err := base64.StdEncoding.DecodeString(response.Body)
+	assert.NoError(t, err)
+
+	zr, err := gzip.NewReader(bytes.NewReader(b))
+	assert.NoError(t, err)
+
+	decompressed, err := io.ReadAll(zr)
+	assert.NoError(t, err)
+	assert.Equal(t, body, string(decompressed))
+}
+
+func TestGzipResponses_sharedHeaders(t *testing.T) {
+	shared := map[string]string{"Content-Type": "application/json"}
+	handler := GzipResponses(0)(gzipHandler(events.APIGatewayProxyResponse{
+		StatusCode: 200,
+		Headers:    shared,
+		Body:       "yolo",
+	}))
+
+	response, err := handler(gzipContext("gzip"))
+	assert.NoError(t, err)
+	assert.Equal(t, "gzip", response.Headers["Content-Encoding"])
+	assert.Equal(t, "application/json", response.Headers["Content-Type"])
+	assert.Equal(t, map[string]string{"Content-Type": "application/json"}, shared)
+}
+
+func TestGzipResponses_skipped(t *testing.T) {
+	large := strings.Repeat("a", 2048)
+
+	cases := []struct {
+		acceptEncoding string
+		response       events.APIGatewayProxyResponse
+	}{
+		{"gzip", events.APIGatewayProxyResponse{StatusCode: 200, Body: "small"}},
+		{"", events.APIGatewayProxyResponse{StatusCode: 200, Body: large}},
+		{"deflate, br", events.APIGatewayProxyResponse{StatusCode: 200, Body: large}},
+		{"gzip", events.APIGatewayProxyResponse{StatusCode: 200, Body: large, IsBase64Encoded: true}},
+		{"gzip", events.APIGatewayProxyResponse{StatusCode: 200, Body: large, Headers: map[string]string{"content-encoding": "br"}}},
+	}
+
+	for _, c := range cases {
+		response, err := GzipResponses(1024)(gzipHandler(c.response))(gzipContext(c.acceptEncoding))
+
+		assert.NoError(t, err)
+		assert.Equal(t, c.response, response)
+	}
+}
+
+func TestGzipResponses_error(t *testing.T) {
+	handler := func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: 500, Body: strings.Repeat("a", 2048)}, errors.New("failed")
+	}
+
+	response, err := GzipResponses(0)(handler)(gzipContext("gzip"))
+
+	assert.Error(t, err)
+	assert.False(t, response.IsBase64Encoded)
+}
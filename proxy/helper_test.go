@@ -53,3 +53,15 @@ func dummy(v interface{}, category string) interface{} {
 func dummyAPIGatewayV2HTTPRequest(category string) events.APIGatewayV2HTTPRequest {
 	return *dummy(&events.APIGatewayV2HTTPRequest{}, category).(*events.APIGatewayV2HTTPRequest)
 }
+
+func dummyAPIGatewayProxyRequest(category string) events.APIGatewayProxyRequest {
+	return *dummy(&events.APIGatewayProxyRequest{}, category).(*events.APIGatewayProxyRequest)
+}
+
+func dummyLambdaFunctionURLRequest(category string) events.LambdaFunctionURLRequest {
+	return *dummy(&events.LambdaFunctionURLRequest{}, category).(*events.LambdaFunctionURLRequest)
+}
+
+func dummyALBTargetGroupRequest(category string) events.ALBTargetGroupRequest {
+	return *dummy(&events.ALBTargetGroupRequest{}, category).(*events.ALBTargetGroupRequest)
+}
@@ -0,0 +1,46 @@
+package proxy
+
+import (
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/pkg/errors"
+)
+
+// HTTPError is an error with an http status code. When JSON errors are enabled
+// on the router, a handler returning an HTTPError results in a response with
+// the given status and the message in the json body.
+type HTTPError struct {
+	Status  int
+	Message string
+}
+
+// Error returns the error message.
+func (e HTTPError) Error() string {
+	return e.Message
+}
+
+// EnableJSONErrors configures the router to convert any error returned while
+// routing, and not handled by the CatchError handler, into a json response of
+// the form {"error":"..."}. HTTPError values use their status and message, all
+// other errors result in a 500 response with a generic message.
+func (router *Router) EnableJSONErrors() {
+	router.jsonErrors = true
+}
+
+// jsonError returns the json response for the error.
+func jsonError(err error) events.APIGatewayProxyResponse {
+	status := 500
+	message := "internal server error"
+
+	var value HTTPError
+	var pointer *HTTPError
+
+	switch {
+	case errors.As(err, &value):
+		status, message = value.Status, value.Message
+	case errors.As(err, &pointer):
+		status, message = pointer.Status, pointer.Message
+	}
+
+	response, _ := JSON(status, map[string]string{"error": message})
+	return response
+}
@@ -0,0 +1,90 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPError_Error(t *testing.T) {
+	err := HTTPError{Status: 400, Message: "bad request"}
+	assert.Equal(t, "bad request", err.Error())
+}
+
+func TestJsonError(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		body   string
+	}{
+		{HTTPError{Status: 400, Message: "bad id"}, 400, `{"error":"bad id"}`},
+		{&HTTPError{Status: 403, Message: "forbidden"}, 403, `{"error":"forbidden"}`},
+		{errors.Wrap(HTTPError{Status: 409, Message: "conflict"}, "wrapped"), 409, `{"error":"conflict"}`},
+		{fmt.Errorf("wrapped: %w", &HTTPError{Status: 422, Message: "invalid"}), 422, `{"error":"invalid"}`},
+		{fmt.Errorf("boom"), 500, `{"error":"internal server error"}`},
+	}
+
+	for _, test := range tests {
+		response := jsonError(test.err)
+		assert.Equal(t, test.status, response.StatusCode)
+		assert.Equal(t, test.body, response.Body)
+		assert.Equal(t, "application/json", response.Headers["Content-Type"])
+	}
+}
+
+func TestRouter_EnableJSONErrors(t *testing.T) {
+	r := &Router{}
+	r.EnableJSONErrors()
+	r.GET("/bad", func(*RouteContext) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{}, HTTPError{Status: 400, Message: "bad id"}
+	})
+	r.GET("/boom", func(*RouteContext) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{}, fmt.Errorf("boom")
+	})
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/bad"))
+	assert.Nil(t, err)
+	assert.Equal(t, 400, response.StatusCode)
+	assert.Equal(t, `{"error":"bad id"}`, response.Body)
+
+	response, err = r.Route(context.Background(), testRequest(GET, "/boom"))
+	assert.Nil(t, err)
+	assert.Equal(t, 500, response.StatusCode)
+	assert.Equal(t, `{"error":"internal server error"}`, response.Body)
+
+	response, err = r.Route(context.Background(), testRequest(GET, "/missing"))
+	assert.Nil(t, err)
+	assert.Equal(t, 404, response.StatusCode)
+	assert.Equal(t, `{"error":"'GET /missing' not found"}`, response.Body)
+}
+
+func TestRouter_EnableJSONErrors_catchError(t *testing.T) {
+	r := &Router{}
+	r.EnableJSONErrors()
+	r.AddErrorHandler(func(ctx context.Context, request events.APIGatewayV2HTTPRequest, err error) (events.APIGatewayProxyResponse, error) {
+		return Text(418, err.Error())
+	})
+	r.GET("/bad", func(*RouteContext) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{}, HTTPError{Status: 400, Message: "bad id"}
+	})
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/bad"))
+	assert.Nil(t, err)
+	assert.Equal(t, 418, response.StatusCode)
+	assert.Equal(t, "bad id", response.Body)
+}
+
+func TestRouter_Route_notFound_httpError(t *testing.T) {
+	r := &Router{}
+
+	_, err := r.Route(context.Background(), testRequest(GET, "/missing"))
+
+	var httpErr HTTPError
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, 404, httpErr.Status)
+	assert.Equal(t, "'GET /missing' not found", err.Error())
+}
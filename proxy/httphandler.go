@@ -0,0 +1,108 @@
+package proxy
+
+import (
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/pkg/errors"
+)
+
+// HTTPToV2Request converts a net/http request into the api gateway v2 (http)
+// request shape used by the router. Header names are lowercased and cookies are
+// moved out of the headers, as api gateway v2 does. Bodies that are not valid
+// utf-8 are base64 encoded.
+func HTTPToV2Request(r *http.Request) (events.APIGatewayV2HTTPRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return events.APIGatewayV2HTTPRequest{}, errors.Wrap(err, "failed reading request body")
+	}
+
+	headers := joinHeaders(nil, r.Header)
+	delete(headers, "cookie")
+
+	cookies := []string{}
+	for _, c := range r.Cookies() {
+		cookies = append(cookies, c.String())
+	}
+
+	sourceIP, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		sourceIP = r.RemoteAddr
+	}
+
+	request := events.APIGatewayV2HTTPRequest{
+		Version:               "2.0",
+		RawPath:               r.URL.EscapedPath(),
+		RawQueryString:        r.URL.RawQuery,
+		Cookies:               cookies,
+		Headers:               headers,
+		QueryStringParameters: joinValues(nil, r.URL.Query()),
+		Body:                  string(body),
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			DomainName: r.Host,
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				Protocol:  r.Proto,
+				SourceIP:  sourceIP,
+				UserAgent: r.UserAgent(),
+			},
+		},
+	}
+
+	if !utf8.Valid(body) {
+		request.Body = base64.StdEncoding.EncodeToString(body)
+		request.IsBase64Encoded = true
+	}
+
+	return request, nil
+}
+
+// HTTPHandler returns a http.Handler that routes net/http requests through the
+// router. This is intended for local testing, e.g. with httptest, and local
+// development servers.
+//
+// Any error returned by the router results in a 500 response, mirroring how
+// api gateway handles lambda errors.
+func (router *Router) HTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request, err := HTTPToV2Request(r)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		response, err := router.Route(r.Context(), request)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		body := []byte(response.Body)
+
+		if response.IsBase64Encoded {
+			body, err = base64.StdEncoding.DecodeString(response.Body)
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		for k, v := range response.Headers {
+			w.Header().Set(k, v)
+		}
+
+		for k, vs := range response.MultiValueHeaders {
+			for _, v := range vs {
+				w.Header().Add(k, v)
+			}
+		}
+
+		w.WriteHeader(response.StatusCode)
+		_, _ = w.Write(body)
+	})
+}
@@ -0,0 +1,103 @@
+package proxy
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPToV2Request(t *testing.T) {
+	r := httptest.NewRequest("POST", "/wowza/42?tag=a&tag=b", strings.NewReader("dude=the+dude"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("User-Agent", "curl/7.79.1")
+	r.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+
+	request, err := HTTPToV2Request(r)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/wowza/42", request.RawPath)
+	assert.Equal(t, "tag=a&tag=b", request.RawQueryString)
+	assert.Equal(t, "POST", request.RequestContext.HTTP.Method)
+	assert.Equal(t, "192.0.2.1", request.RequestContext.HTTP.SourceIP)
+	assert.Equal(t, "curl/7.79.1", request.RequestContext.HTTP.UserAgent)
+	assert.Equal(t, "application/x-www-form-urlencoded", request.Headers["content-type"])
+	assert.NotContains(t, request.Headers, "cookie")
+	assert.Equal(t, []string{"session=abc"}, request.Cookies)
+	assert.Equal(t, map[string]string{"tag": "a,b"}, request.QueryStringParameters)
+	assert.Equal(t, "dude=the+dude", request.Body)
+	assert.False(t, request.IsBase64Encoded)
+}
+
+func TestHTTPToV2Request_binary(t *testing.T) {
+	r := httptest.NewRequest("POST", "/bin", strings.NewReader("\xff\xfe"))
+
+	request, err := HTTPToV2Request(r)
+
+	assert.NoError(t, err)
+	assert.True(t, request.IsBase64Encoded)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("\xff\xfe")), request.Body)
+}
+
+func TestRouter_HTTPHandler(t *testing.T) {
+	r := &Router{}
+
+	r.POST("/wowza/(?P<id>[0-9]+)", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{
+			StatusCode:        201,
+			Headers:           map[string]string{"Content-Type": "text/plain"},
+			MultiValueHeaders: map[string][]string{"Set-Cookie": {"a=1", "b=2"}},
+			Body:              ctx.Params["id"] + " " + ctx.Params["dude"],
+		}, nil
+	})
+
+	req := httptest.NewRequest("POST", "/wowza/42", strings.NewReader("dude=the+dude"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+
+	r.HTTPHandler().ServeHTTP(rec, req)
+
+	assert.Equal(t, 201, rec.Code)
+	assert.Equal(t, "text/plain", rec.Header().Get("Content-Type"))
+	assert.Equal(t, []string{"a=1", "b=2"}, rec.Header().Values("Set-Cookie"))
+	assert.Equal(t, "42 the dude", rec.Body.String())
+}
+
+func TestRouter_HTTPHandler_base64(t *testing.T) {
+	r := &Router{}
+
+	r.GET("/bin", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{
+			StatusCode:      200,
+			Body:            base64.StdEncoding.EncodeToString([]byte("hey dude!")),
+			IsBase64Encoded: true,
+		}, nil
+	})
+
+	rec := httptest.NewRecorder()
+	r.HTTPHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/bin", nil))
+
+	assert.Equal(t, 200, rec.Code)
+	assert.Equal(t, "hey dude!", rec.Body.String())
+}
+
+func TestRouter_HTTPHandler_error(t *testing.T) {
+	r := &Router{}
+
+	r.GET("/fail", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{}, errors.New("failed")
+	})
+
+	rec := httptest.NewRecorder()
+	r.HTTPHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/fail", nil))
+	assert.Equal(t, 500, rec.Code)
+
+	rec = httptest.NewRecorder()
+	r.HTTPHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/nope", nil))
+	assert.Equal(t, 500, rec.Code)
+}
@@ -1,5 +1,10 @@
 package proxy
 
+import (
+	"fmt"
+	"strings"
+)
+
 // HttpMethod is an enum of the standard Http Methods.
 type HttpMethod int
 
@@ -14,3 +19,38 @@ const (
 	TRACE
 	PATCH
 )
+
+// httpMethodNames maps each HttpMethod to its canonical verb.
+var httpMethodNames = [...]string{
+	GET:     "GET",
+	HEAD:    "HEAD",
+	POST:    "POST",
+	PUT:     "PUT",
+	DELETE:  "DELETE",
+	CONNECT: "CONNECT",
+	OPTIONS: "OPTIONS",
+	TRACE:   "TRACE",
+	PATCH:   "PATCH",
+}
+
+// String returns the canonical uppercase verb of the method or "UNKNOWN" when
+// the method is out of range.
+func (m HttpMethod) String() string {
+	if m < 0 || int(m) >= len(httpMethodNames) {
+		return "UNKNOWN"
+	}
+
+	return httpMethodNames[m]
+}
+
+// ParseHttpMethod returns the HttpMethod matching s. The match is case
+// insensitive. An error is returned for unknown methods.
+func ParseHttpMethod(s string) (HttpMethod, error) {
+	for m := GET; m <= PATCH; m++ {
+		if strings.EqualFold(m.String(), s) {
+			return m, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown http method '%s'", s)
+}
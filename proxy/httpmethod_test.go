@@ -0,0 +1,69 @@
+package proxy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHttpMethod_String(t *testing.T) {
+	cases := []struct {
+		m        HttpMethod
+		expected string
+	}{
+		{GET, "GET"},
+		{HEAD, "HEAD"},
+		{POST, "POST"},
+		{PUT, "PUT"},
+		{DELETE, "DELETE"},
+		{CONNECT, "CONNECT"},
+		{OPTIONS, "OPTIONS"},
+		{TRACE, "TRACE"},
+		{PATCH, "PATCH"},
+		{HttpMethod(-1), "UNKNOWN"},
+		{HttpMethod(9), "UNKNOWN"},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, c.m.String())
+	}
+}
+
+func TestParseHttpMethod(t *testing.T) {
+	cases := []struct {
+		s        string
+		expected HttpMethod
+	}{
+		{"GET", GET},
+		{"get", GET},
+		{"post", POST},
+		{"Patch", PATCH},
+		{"OPTIONS", OPTIONS},
+	}
+
+	for _, c := range cases {
+		actual, err := ParseHttpMethod(c.s)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, actual)
+	}
+}
+
+func TestParseHttpMethod_roundTrip(t *testing.T) {
+	for m := GET; m <= PATCH; m++ {
+		actual, err := ParseHttpMethod(m.String())
+		assert.NoError(t, err)
+		assert.Equal(t, m, actual)
+
+		actual, err = ParseHttpMethod(strings.ToLower(m.String()))
+		assert.NoError(t, err)
+		assert.Equal(t, m, actual)
+	}
+}
+
+func TestParseHttpMethod_error(t *testing.T) {
+	for _, s := range []string{"", "YOLO", "GETS", " GET"} {
+		_, err := ParseHttpMethod(s)
+		assert.Error(t, err)
+	}
+}
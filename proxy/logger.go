@@ -0,0 +1,58 @@
+package proxy
+
+import (
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// LogEntry describes a single request handled by the router.
+//
+// Route and Pattern are empty when no route was matched. Error is the error
+// returned while routing, before any CatchError handler was applied.
+type LogEntry struct {
+	Method     string
+	Path       string
+	Route      string
+	Pattern    string
+	StatusCode int
+	Duration   time.Duration
+	Error      error
+}
+
+// SetLogger sets the function the router calls with a LogEntry for every
+// request it routes. By default no logging occurs.
+func (router *Router) SetLogger(fn func(LogEntry)) {
+	router.logger = fn
+}
+
+// now is used internally to assist stubs on time.Now() for testing
+func (router *Router) now() time.Time {
+	if router.nowFunc != nil {
+		return router.nowFunc()
+	}
+
+	return time.Now()
+}
+
+// log emits a LogEntry for the request if a logger is set.
+func (router *Router) log(start time.Time, request events.APIGatewayV2HTTPRequest, route *Route, response events.APIGatewayProxyResponse, err error) {
+	if router.logger == nil {
+		return
+	}
+
+	entry := LogEntry{
+		Method:     request.RequestContext.HTTP.Method,
+		Path:       request.RawPath,
+		StatusCode: response.StatusCode,
+		Duration:   router.now().Sub(start),
+		Error:      err,
+	}
+
+	if route != nil {
+		entry.Route = route.String()
+		entry.Pattern = route.Regex.String()
+	}
+
+	router.logger(entry)
+}
@@ -0,0 +1,74 @@
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func stepClock(step time.Duration) func() time.Time {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+
+	return func() time.Time {
+		t := now
+		now = now.Add(step)
+		return t
+	}
+}
+
+func TestRouter_SetLogger(t *testing.T) {
+	entries := []LogEntry{}
+
+	r := &Router{}
+	r.nowFunc = stepClock(5 * time.Millisecond)
+	r.SetLogger(func(entry LogEntry) { entries = append(entries, entry) })
+	r.GET("/route/(?P<id>[0-9]+)", testHandler)
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/route/4"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+
+	expected := LogEntry{
+		Method:     "GET",
+		Path:       "/route/4",
+		Route:      "GET ^/route/(?P<id>[0-9]+)/?$",
+		Pattern:    "^/route/(?P<id>[0-9]+)/?$",
+		StatusCode: 200,
+		Duration:   5 * time.Millisecond,
+	}
+
+	assert.Equal(t, []LogEntry{expected}, entries)
+}
+
+func TestRouter_SetLogger_notFound(t *testing.T) {
+	entries := []LogEntry{}
+
+	r := &Router{}
+	r.nowFunc = stepClock(time.Millisecond)
+	r.SetLogger(func(entry LogEntry) { entries = append(entries, entry) })
+	r.AddErrorHandler(func(ctx context.Context, request events.APIGatewayV2HTTPRequest, err error) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: 404}, nil
+	})
+
+	response, err := r.Route(context.Background(), testRequest(POST, "/nope"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 404, response.StatusCode)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "POST", entries[0].Method)
+	assert.Equal(t, "/nope", entries[0].Path)
+	assert.Equal(t, "", entries[0].Route)
+	assert.Equal(t, "", entries[0].Pattern)
+	assert.Equal(t, 404, entries[0].StatusCode)
+	assert.Equal(t, time.Millisecond, entries[0].Duration)
+	assert.EqualError(t, entries[0].Error, "'POST /nope' not found")
+}
+
+func TestRouter_now(t *testing.T) {
+	r := &Router{}
+	assert.WithinDuration(t, time.Now(), r.now(), time.Second)
+}
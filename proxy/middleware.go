@@ -0,0 +1,32 @@
+package proxy
+
+// Middleware wraps a RouteHandler to add behavior around it. A middleware may
+// short-circuit the request by returning a response without calling next.
+//
+// Both router and route middleware run after a route has matched, so the
+// RouteContext they receive already has its Params, MatchedRoute and
+// MatchedPath set. This allows, for example, authorization based on a path
+// param:
+//
+//	func ownerOnly(next RouteHandler) RouteHandler {
+//		return func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+//			if ctx.Params["owner"] != userFromToken(ctx) {
+//				return Text(403, "forbidden")
+//			}
+//
+//			return next(ctx)
+//		}
+//	}
+//
+//	router.GET("/users/:owner/files", filesHandler, ownerOnly)
+type Middleware func(next RouteHandler) RouteHandler
+
+// chain wraps the handler with the middleware so that the first middleware is
+// the outermost and therefore runs first.
+func chain(handler RouteHandler, middleware ...Middleware) RouteHandler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	return handler
+}
@@ -0,0 +1,95 @@
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func appendMiddleware(name string, calls *[]string) Middleware {
+	return func(next RouteHandler) RouteHandler {
+		return func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+			*calls = append(*calls, name)
+			return next(ctx)
+		}
+	}
+}
+
+func TestChain(t *testing.T) {
+	calls := []string{}
+
+	handler := func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		calls = append(calls, "handler")
+		return events.APIGatewayProxyResponse{StatusCode: 200}, nil
+	}
+
+	h := chain(handler, appendMiddleware("first", &calls), appendMiddleware("second", &calls))
+
+	response, err := h(&RouteContext{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, []string{"first", "second", "handler"}, calls)
+}
+
+func TestChain_none(t *testing.T) {
+	response, err := chain(testHandler)(&RouteContext{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+}
+
+func ownerOnly(next RouteHandler) RouteHandler {
+	return func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		if ctx.Params["owner"] != ctx.Header("x-user") {
+			return Text(403, "forbidden")
+		}
+
+		return next(ctx)
+	}
+}
+
+func TestMiddleware_denyOnPathParam(t *testing.T) {
+	r := &Router{}
+	r.GET("/users/:owner/files", testHandler, ownerOnly)
+
+	request := testRequest(GET, "/users/42/files")
+	request.Headers["x-user"] = "42"
+
+	response, err := r.Route(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+
+	request.Headers["x-user"] = "7"
+
+	response, err = r.Route(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, 403, response.StatusCode)
+	assert.Equal(t, "forbidden", response.Body)
+}
+
+func TestMiddleware_routerMiddlewareHasMatchedRoute(t *testing.T) {
+	var matched *Route
+
+	r := &Router{}
+	r.Use(func(next RouteHandler) RouteHandler {
+		return func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+			matched = ctx.MatchedRoute
+
+			if ctx.Params["owner"] == "root" {
+				return Text(403, "forbidden")
+			}
+
+			return next(ctx)
+		}
+	})
+	r.GET("/users/:owner/files", testHandler)
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/users/root/files"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 403, response.StatusCode)
+	assert.Equal(t, "^/users/(?P<owner>[^/]+)/files/?$", matched.Regex.String())
+}
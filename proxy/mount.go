@@ -0,0 +1,115 @@
+package proxy
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/pkg/errors"
+)
+
+// mount is a sub router mounted under a path prefix.
+type mount struct {
+	prefix string
+	router *Router
+}
+
+// matches returns true if the path is within the mount prefix.
+func (m *mount) matches(path string) bool {
+	return path == m.prefix || strings.HasPrefix(path, m.prefix+"/")
+}
+
+// Mount incorporates the routes of the sub router under the given literal path
+// prefix. The routes are copied when mounted, so routes added to the sub router
+// afterwards are not included. Any build errors of the sub router are added to
+// this router.
+//
+// The sub router's CatchError handler and middleware only apply to requests
+// matching the sub router's routes. The execution order for a mounted route is:
+//
+//  1. Router middleware
+//  2. Sub router CatchError handler, for errors returned by what follows
+//  3. Sub router middleware
+//  4. Route middleware
+//  5. The route handler
+//
+// The sub router's CatchAll handler only applies to requests within the prefix
+// that match no route. It takes precedence over this router's CatchAll.
+func (router *Router) Mount(prefix string, sub *Router) {
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	for _, err := range sub.errors {
+		router.AddBuildError(errors.Wrapf(err, "failed mounting router at '%s'", prefix))
+	}
+
+	for _, route := range sub.Routes {
+		pattern := "^" + regexp.QuoteMeta(prefix) + strings.TrimPrefix(route.Regex.String(), "^")
+
+		rx, err := regexp.Compile(pattern)
+		if err != nil {
+			router.AddBuildError(errors.Wrapf(err, "failed compiling mounted regex pattern '%s'", pattern))
+			continue
+		}
+
+		middleware := []Middleware{sub.wrapCatchError, sub.wrap}
+
+		router.AddRoute(&Route{
+			Name:            route.Name,
+			Method:          route.Method,
+			Regex:           rx,
+			Handler:         route.Handler,
+			Middleware:      append(middleware, route.Middleware...),
+			ParamPrecedence: route.ParamPrecedence,
+			FormMethods:     route.FormMethods,
+			MatchQuery:      route.MatchQuery,
+		})
+	}
+
+	if sub.CatchAll != nil {
+		router.mounts = append(router.mounts, &mount{prefix: prefix, router: sub})
+	}
+}
+
+// wrap is the route middleware that applies the router middleware to mounted
+// routes. It is resolved at request time so middleware added after mounting
+// still applies.
+func (router *Router) wrap(next RouteHandler) RouteHandler {
+	return func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return chain(next, router.middleware...)(ctx)
+	}
+}
+
+// wrapCatchError is the route middleware that passes errors of mounted routes
+// to the router's CatchErrorV2 or CatchError handler, if set.
+func (router *Router) wrapCatchError(next RouteHandler) RouteHandler {
+	return func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		response, err := next(ctx)
+
+		if err != nil && router.hasCatchError() {
+			return router.catchError(ctx, err)
+		}
+
+		return response, err
+	}
+}
+
+// mountCatchAll executes the CatchAll handler of the first mounted router whose
+// prefix matches the request. It returns false if there is no match.
+func (router *Router) mountCatchAll(ctx context.Context, request events.APIGatewayV2HTTPRequest) (events.APIGatewayProxyResponse, bool, error) {
+	for _, m := range router.mounts {
+		if !m.matches(request.RawPath) {
+			continue
+		}
+
+		response, err := m.router.catchAll(ctx, request)
+
+		if err != nil && m.router.hasCatchError() {
+			response, err = m.router.catchError(newRouteContext(ctx, request, map[string]string{}), err)
+		}
+
+		return response, true, err
+	}
+
+	return events.APIGatewayProxyResponse{}, false, nil
+}
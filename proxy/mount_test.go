@@ -0,0 +1,156 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func bodyHandler(body string) RouteHandler {
+	return func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: 200, Body: body + ctx.Params["id"]}, nil
+	}
+}
+
+func statusCatchAll(status int, body string) CatchAllHandler {
+	return func(ctx context.Context, request events.APIGatewayV2HTTPRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: status, Body: body}, nil
+	}
+}
+
+func TestRouter_Mount(t *testing.T) {
+	users := &Router{}
+	users.GET("/(?P<id>[0-9]+)", bodyHandler("user "))
+	users.GET("", bodyHandler("users"))
+
+	orders := &Router{}
+	orders.GET("/(?P<id>[0-9]+)", bodyHandler("order "))
+
+	r := &Router{}
+	r.GET("/", bodyHandler("root"))
+	r.Mount("/users", users)
+	r.Mount("/orders/", orders)
+
+	assert.True(t, r.Valid())
+	assert.Len(t, r.Routes, 4)
+	assert.Equal(t, "GET ^/users/(?P<id>[0-9]+)/?$", r.Routes[1].String())
+	assert.Equal(t, "GET ^/orders/(?P<id>[0-9]+)/?$", r.Routes[3].String())
+
+	cases := []struct {
+		path     string
+		expected string
+	}{
+		{"/", "root"},
+		{"/users", "users"},
+		{"/users/4", "user 4"},
+		{"/orders/2", "order 2"},
+	}
+
+	for _, c := range cases {
+		response, err := r.Route(context.Background(), testRequest(GET, c.path))
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, response.Body)
+	}
+
+	_, err := r.Route(context.Background(), testRequest(GET, "/4"))
+	assert.Error(t, err)
+}
+
+func TestRouter_Mount_catchAll(t *testing.T) {
+	sub := &Router{}
+	sub.GET("/yolo", testHandler)
+	sub.AddCatchAllHandler(statusCatchAll(404, "sub not found"))
+
+	r := &Router{}
+	r.AddCatchAllHandler(statusCatchAll(404, "parent not found"))
+	r.Mount("/sub", sub)
+
+	cases := []struct {
+		path     string
+		expected string
+	}{
+		{"/sub/nope", "sub not found"},
+		{"/sub", "sub not found"},
+		{"/subway", "parent not found"},
+		{"/nope", "parent not found"},
+	}
+
+	for _, c := range cases {
+		response, err := r.Route(context.Background(), testRequest(GET, c.path))
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, response.Body)
+	}
+}
+
+func TestRouter_Mount_catchError(t *testing.T) {
+	errorHandler := func(body string) ErrorHandler {
+		return func(ctx context.Context, request events.APIGatewayV2HTTPRequest, err error) (events.APIGatewayProxyResponse, error) {
+			return events.APIGatewayProxyResponse{StatusCode: 500, Body: body + err.Error()}, nil
+		}
+	}
+
+	failHandler := func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{}, errors.New("failed")
+	}
+
+	sub := &Router{}
+	sub.GET("/fail", failHandler)
+	sub.AddErrorHandler(errorHandler("sub "))
+
+	plain := &Router{}
+	plain.GET("/fail", failHandler)
+
+	r := &Router{}
+	r.GET("/fail", failHandler)
+	r.AddErrorHandler(errorHandler("parent "))
+	r.Mount("/sub", sub)
+	r.Mount("/plain", plain)
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/sub/fail"))
+	assert.NoError(t, err)
+	assert.Equal(t, "sub failed", response.Body)
+
+	response, err = r.Route(context.Background(), testRequest(GET, "/plain/fail"))
+	assert.NoError(t, err)
+	assert.Equal(t, "parent failed", response.Body)
+
+	response, err = r.Route(context.Background(), testRequest(GET, "/fail"))
+	assert.NoError(t, err)
+	assert.Equal(t, "parent failed", response.Body)
+}
+
+func TestRouter_Mount_middleware(t *testing.T) {
+	calls := []string{}
+
+	sub := &Router{}
+	sub.Use(appendMiddleware("sub", &calls))
+	sub.GET("/yolo", testHandler, appendMiddleware("route", &calls))
+
+	r := &Router{}
+	r.Use(appendMiddleware("parent", &calls))
+	r.GET("/yolo", testHandler)
+	r.Mount("/sub", sub)
+
+	_, err := r.Route(context.Background(), testRequest(GET, "/sub/yolo"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"parent", "sub", "route"}, calls)
+
+	calls = []string{}
+	_, err = r.Route(context.Background(), testRequest(GET, "/yolo"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"parent"}, calls)
+}
+
+func TestRouter_Mount_buildErrors(t *testing.T) {
+	sub := &Router{}
+	sub.GET("/(?P<bad", testHandler)
+
+	r := &Router{}
+	r.Mount("/sub", sub)
+
+	assert.False(t, r.Valid())
+	assert.Contains(t, r.BuildErrors().Error(), "failed mounting router at '/sub'")
+}
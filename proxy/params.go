@@ -0,0 +1,165 @@
+package proxy
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/pkg/errors"
+)
+
+// ParamSource identifies where a RouteContext param was extracted from.
+type ParamSource int
+
+const (
+	// ParamSourceForm is the 'application/x-www-form-urlencoded' body of a POST,
+	// PUT or PATCH request, see DefaultFormMethods.
+	ParamSourceForm ParamSource = iota
+
+	// ParamSourceRegex is the route's named regex capture groups.
+	ParamSourceRegex
+
+	// ParamSourceQuery is the query string.
+	ParamSourceQuery
+
+	// ParamSourcePath is the aws api gateway configured PathParameters.
+	ParamSourcePath
+)
+
+// String returns the name of the param source.
+func (source ParamSource) String() string {
+	switch source {
+	case ParamSourceForm:
+		return "form"
+	case ParamSourceRegex:
+		return "regex"
+	case ParamSourceQuery:
+		return "query"
+	case ParamSourcePath:
+		return "path"
+	}
+
+	return fmt.Sprintf("ParamSource(%d)", int(source))
+}
+
+// DefaultParamPrecedence is the param precedence used when a route has no
+// ParamPrecedence set, highest precedence first.
+var DefaultParamPrecedence = []ParamSource{
+	ParamSourceForm,
+	ParamSourceRegex,
+	ParamSourceQuery,
+	ParamSourcePath,
+}
+
+// DefaultFormMethods are the methods whose urlencoded form body params are
+// extracted when a route has no FormMethods set.
+var DefaultFormMethods = []HttpMethod{POST, PUT, PATCH}
+
+// SetFormMethods sets the methods whose urlencoded form body params are
+// extracted, for routes added via the router or its groups after it is set.
+func (router *Router) SetFormMethods(methods ...HttpMethod) {
+	router.formMethods = methods
+}
+
+// formMethod returns true if the form body params of a request with the given
+// method are extracted.
+func (route *Route) formMethod(method string) bool {
+	methods := route.FormMethods
+	if methods == nil {
+		methods = DefaultFormMethods
+	}
+
+	for _, m := range methods {
+		if m.String() == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetParamPrecedence sets the param precedence, highest precedence first, of
+// routes added via the router or its groups after it is set. Sources that
+// aren't listed are not extracted.
+func (router *Router) SetParamPrecedence(sources ...ParamSource) {
+	router.paramPrecedence = sources
+}
+
+// paramPrecedence returns the route's param precedence, highest precedence
+// first.
+func (route *Route) paramPrecedence() []ParamSource {
+	if route.ParamPrecedence != nil {
+		return route.ParamPrecedence
+	}
+
+	return DefaultParamPrecedence
+}
+
+// extractParams extracts the params from the request in reverse order of
+// precedence, so that sources with a higher precedence overwrite those with a
+// lower one.
+func (route *Route) extractParams(request events.APIGatewayV2HTTPRequest, groups []string) (map[string]string, error) {
+	params := make(map[string]string)
+	sources := route.paramPrecedence()
+
+	for i := len(sources) - 1; i >= 0; i-- {
+		err := route.extractParamsFrom(sources[i], params, request, groups)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return params, nil
+}
+
+// extractParamsFrom extracts the params from the single source into params.
+func (route *Route) extractParamsFrom(source ParamSource, params map[string]string, request events.APIGatewayV2HTTPRequest, groups []string) error {
+	switch source {
+	case ParamSourcePath:
+		route.extractParamsFromPath(params, request)
+	case ParamSourceQuery:
+		route.extractParamsFromQueryString(params, request)
+	case ParamSourceRegex:
+		route.extractParamsFromURIRegex(params, groups)
+	case ParamSourceForm:
+		err := route.extractParamsFromFormPost(params, request)
+		if err != nil {
+			return errors.Wrapf(err, "failed extractParamsFromFormPost")
+		}
+	}
+
+	return nil
+}
+
+// paramConflict returns a 400 HTTPError if a param is set to different values
+// by multiple sources of the route's param precedence, see Router.StrictParams.
+func (route *Route) paramConflict(request events.APIGatewayV2HTTPRequest, groups []string) error {
+	seen := make(map[string]ParamSource)
+	values := make(map[string]string)
+
+	for _, source := range route.paramPrecedence() {
+		params := make(map[string]string)
+
+		err := route.extractParamsFrom(source, params, request, groups)
+		if err != nil {
+			return err
+		}
+
+		for k, v := range params {
+			first, ok := seen[k]
+			if !ok {
+				seen[k] = source
+				values[k] = v
+				continue
+			}
+
+			if values[k] != v {
+				return HTTPError{
+					Status:  400,
+					Message: fmt.Sprintf("param '%s' is set by both %s and %s", k, first, source),
+				}
+			}
+		}
+	}
+
+	return nil
+}
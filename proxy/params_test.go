@@ -0,0 +1,200 @@
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func paramsRequest() events.APIGatewayV2HTTPRequest {
+	request := testRequest(GET, "/users/regex")
+	request.QueryStringParameters = map[string]string{"id": "query"}
+	request.PathParameters = map[string]string{"id": "path"}
+
+	return request
+}
+
+func TestRoute_Context_defaultPrecedence(t *testing.T) {
+	r, err := NewRoute(GET, "/users/(?P<id>[a-z]+)", testHandler)
+	assert.NoError(t, err)
+
+	request := paramsRequest()
+	_, groups := r.IsMatch(request)
+
+	ctx, err := r.Context(context.Background(), request, groups)
+	assert.NoError(t, err)
+	assert.Equal(t, "regex", ctx.Params["id"])
+
+	request.RawPath = "/users"
+	r, err = NewRoute(GET, "/users", testHandler)
+	assert.NoError(t, err)
+	_, groups = r.IsMatch(request)
+
+	ctx, err = r.Context(context.Background(), request, groups)
+	assert.NoError(t, err)
+	assert.Equal(t, "query", ctx.Params["id"])
+}
+
+func TestRoute_Context_paramPrecedence(t *testing.T) {
+	r, err := NewRoute(GET, "/users", testHandler)
+	assert.NoError(t, err)
+	r.ParamPrecedence = []ParamSource{ParamSourcePath, ParamSourceQuery}
+
+	request := paramsRequest()
+	request.RawPath = "/users"
+	_, groups := r.IsMatch(request)
+
+	ctx, err := r.Context(context.Background(), request, groups)
+	assert.NoError(t, err)
+	assert.Equal(t, "path", ctx.Params["id"])
+}
+
+func TestRoute_Context_paramPrecedenceOmitted(t *testing.T) {
+	r, err := NewRoute(GET, "/users/(?P<id>[a-z]+)", testHandler)
+	assert.NoError(t, err)
+	r.ParamPrecedence = []ParamSource{ParamSourceQuery}
+
+	request := paramsRequest()
+	request.QueryStringParameters = map[string]string{"q": "yolo"}
+	_, groups := r.IsMatch(request)
+
+	ctx, err := r.Context(context.Background(), request, groups)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"q": "yolo"}, ctx.Params)
+}
+
+func TestRouter_SetParamPrecedence(t *testing.T) {
+	r := &Router{}
+	r.SetParamPrecedence(ParamSourcePath, ParamSourceRegex, ParamSourceQuery, ParamSourceForm)
+	r.GET("/users/(?P<id>[a-z]+)", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: 200, Body: ctx.Params["id"]}, nil
+	})
+
+	response, err := r.Route(context.Background(), paramsRequest())
+	assert.NoError(t, err)
+	assert.Equal(t, "path", response.Body)
+}
+
+func TestParamSource_String(t *testing.T) {
+	assert.Equal(t, "form", ParamSourceForm.String())
+	assert.Equal(t, "regex", ParamSourceRegex.String())
+	assert.Equal(t, "query", ParamSourceQuery.String())
+	assert.Equal(t, "path", ParamSourcePath.String())
+	assert.Equal(t, "ParamSource(9)", ParamSource(9).String())
+}
+
+func TestRouter_StrictParams(t *testing.T) {
+	r := &Router{StrictParams: true}
+	r.GET("/users/(?P<id>[a-z]+)", testHandler)
+
+	request := testRequest(GET, "/users/regex")
+	request.QueryStringParameters = map[string]string{"id": "query"}
+
+	_, err := r.Route(context.Background(), request)
+
+	var httpErr HTTPError
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, 400, httpErr.Status)
+	assert.Equal(t, "param 'id' is set by both regex and query", httpErr.Message)
+}
+
+func TestRouter_StrictParams_catchError(t *testing.T) {
+	r := &Router{StrictParams: true}
+	r.GET("/users/(?P<id>[a-z]+)", testHandler)
+	r.AddErrorHandler(func(ctx context.Context, request events.APIGatewayV2HTTPRequest, err error) (events.APIGatewayProxyResponse, error) {
+		return Text(400, err.Error())
+	})
+
+	request := testRequest(GET, "/users/regex")
+	request.QueryStringParameters = map[string]string{"id": "query"}
+
+	response, err := r.Route(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 400, response.StatusCode)
+	assert.Equal(t, "param 'id' is set by both regex and query", response.Body)
+}
+
+func TestRouter_StrictParams_noConflict(t *testing.T) {
+	r := &Router{StrictParams: true}
+	r.GET("/users/(?P<id>[a-z]+)", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return Text(200, ctx.Params["id"])
+	})
+
+	request := testRequest(GET, "/users/regex")
+	request.QueryStringParameters = map[string]string{"id": "regex", "page": "2"}
+	request.PathParameters = map[string]string{"id": "regex"}
+
+	response, err := r.Route(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "regex", response.Body)
+}
+
+func TestRouter_StrictParams_disabled(t *testing.T) {
+	r := &Router{}
+	r.GET("/users/(?P<id>[a-z]+)", testHandler)
+
+	_, err := r.Route(context.Background(), paramsRequest())
+
+	assert.NoError(t, err)
+}
+
+func TestRoute_extractParamsFromFormPost_formMethods(t *testing.T) {
+	r, err := NewRoute(PUT, "/hi", testHandler)
+	assert.NoError(t, err)
+
+	for _, method := range []HttpMethod{POST, PUT, PATCH, GET, HEAD, DELETE} {
+		request := testRequest(method, "/hi")
+		request.Headers["content-type"] = "application/x-www-form-urlencoded"
+		request.Body = "super=red+sonya"
+
+		params := map[string]string{}
+		err = r.extractParamsFromFormPost(params, request)
+		assert.NoError(t, err)
+
+		_, ok := params["super"]
+		assert.Equal(t, method == POST || method == PUT || method == PATCH, ok, method.String())
+	}
+}
+
+func TestRouter_Route_putForm(t *testing.T) {
+	r := &Router{}
+	r.PUT("/users/:id", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return Text(200, ctx.Params["id"]+" "+ctx.Params["name"])
+	})
+
+	request := testRequest(PUT, "/users/42")
+	request.Headers["content-type"] = "application/x-www-form-urlencoded"
+	request.Body = "name=red+sonya"
+
+	response, err := r.Route(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "42 red sonya", response.Body)
+}
+
+func TestRouter_SetFormMethods(t *testing.T) {
+	r := &Router{}
+	r.SetFormMethods(POST, DELETE)
+	r.DELETE("/users", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return Text(200, ctx.Params["name"])
+	})
+	r.PUT("/users", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return Text(200, ctx.Params["name"])
+	})
+
+	for method, expected := range map[HttpMethod]string{DELETE: "yolo", PUT: ""} {
+		request := testRequest(method, "/users")
+		request.Headers["content-type"] = "application/x-www-form-urlencoded"
+		request.Body = "name=yolo"
+
+		response, err := r.Route(context.Background(), request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, response.Body, method.String())
+	}
+}
@@ -0,0 +1,41 @@
+package proxy
+
+import (
+	"regexp"
+	"strings"
+)
+
+// segmentPlaceholder matches a path segment that is entirely a ':name' or
+// '*name' placeholder.
+var segmentPlaceholder = regexp.MustCompile(`^([:*])([A-Za-z_][A-Za-z0-9_]*)$`)
+
+// expandPlaceholders translates the placeholder path segments of the pattern
+// into named regex capture groups:
+//
+//	:name  a single segment, expands to '(?P<name>[^/]+)'
+//	*name  the remaining path including slashes, expands to '(?P<name>.*)'. It
+//	       is only expanded as the last segment.
+//
+// A placeholder must make up the whole segment, so the rest of the pattern is
+// left as is and raw regex keeps working. Literal colons such as in
+// '/items:batchGet' are therefore not placeholders, and a literal ':name'
+// segment can be written as '\:name'.
+func expandPlaceholders(pattern string) string {
+	segments := strings.Split(pattern, "/")
+
+	for i, segment := range segments {
+		m := segmentPlaceholder.FindStringSubmatch(segment)
+		if m == nil {
+			continue
+		}
+
+		switch {
+		case m[1] == ":":
+			segments[i] = "(?P<" + m[2] + ">[^/]+)"
+		case i == len(segments)-1:
+			segments[i] = "(?P<" + m[2] + ">.*)"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,126 @@
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandPlaceholders(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		expected string
+	}{
+		{"/users/:id", "/users/(?P<id>[^/]+)"},
+		{"/users/:id/posts/:post_id", "/users/(?P<id>[^/]+)/posts/(?P<post_id>[^/]+)"},
+		{"/files/*path", "/files/(?P<path>.*)"},
+		{"/files/:dir/*path", "/files/(?P<dir>[^/]+)/(?P<path>.*)"},
+		{"/files/*path/more", "/files/*path/more"},
+		{"/users/(?P<id>[^/]+)", "/users/(?P<id>[^/]+)"},
+		{"/users/:", "/users/:"},
+		{"/files/.*", "/files/.*"},
+		{"/v1/items:batchGet", "/v1/items:batchGet"},
+		{"/time/12:30", "/time/12:30"},
+		{`/users/\:id`, `/users/\:id`},
+		{"/users/(?:admin|:id)", "/users/(?:admin|:id)"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, expandPlaceholders(test.pattern), test.pattern)
+	}
+}
+
+func TestNewRoute_wildcardPlaceholder(t *testing.T) {
+	r, err := NewRoute(GET, "/files/*path", testHandler)
+	assert.NoError(t, err)
+
+	request := testRequest(GET, "/files/some/nested/file.txt")
+	matched, groups := r.IsMatch(request)
+	assert.True(t, matched)
+
+	ctx, err := r.Context(context.Background(), request, groups)
+	assert.NoError(t, err)
+	assert.Equal(t, "some/nested/file.txt", ctx.Params["path"])
+}
+
+func TestNewRoute_colonPlaceholder(t *testing.T) {
+	r, err := NewRoute(GET, "/users/:id", testHandler)
+	assert.NoError(t, err)
+
+	request := testRequest(GET, "/users/42")
+	matched, groups := r.IsMatch(request)
+	assert.True(t, matched)
+
+	ctx, err := r.Context(context.Background(), request, groups)
+	assert.NoError(t, err)
+	assert.Equal(t, "42", ctx.Params["id"])
+
+	matched, _ = r.IsMatch(testRequest(GET, "/users/42/posts"))
+	assert.False(t, matched)
+}
+
+func TestNewRoute_colonPlaceholderEquivalentRegex(t *testing.T) {
+	placeholder, err := NewRoute(GET, "/users/:id/posts/:post", testHandler)
+	assert.NoError(t, err)
+
+	regex, err := NewRoute(GET, "/users/(?P<id>[^/]+)/posts/(?P<post>[^/]+)", testHandler)
+	assert.NoError(t, err)
+
+	assert.Equal(t, regex.Regex.String(), placeholder.Regex.String())
+
+	for _, path := range []string{"/users/42/posts/7", "/users/42/posts/7/", "/users/42/posts", "/users//posts/7"} {
+		request := testRequest(GET, path)
+
+		matched, groups := placeholder.IsMatch(request)
+		regexMatched, regexGroups := regex.IsMatch(request)
+
+		assert.Equal(t, regexMatched, matched, path)
+		assert.Equal(t, regexGroups, groups, path)
+	}
+}
+
+func TestNewRoute_literalColon(t *testing.T) {
+	r, err := NewRoute(POST, "/v1/items:batchGet", testHandler)
+	assert.NoError(t, err)
+
+	matched, _ := r.IsMatch(testRequest(POST, "/v1/items:batchGet"))
+	assert.True(t, matched)
+
+	matched, _ = r.IsMatch(testRequest(POST, "/v1/items"))
+	assert.False(t, matched)
+
+	r, err = NewRoute(GET, `/users/\:id`, testHandler)
+	assert.NoError(t, err)
+
+	matched, _ = r.IsMatch(testRequest(GET, "/users/:id"))
+	assert.True(t, matched)
+
+	matched, _ = r.IsMatch(testRequest(GET, "/users/42"))
+	assert.False(t, matched)
+}
+
+func TestRouter_colonPlaceholder(t *testing.T) {
+	r := &Router{}
+	r.GETNamed("user", "/users/:id", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return Text(200, ctx.Params["id"])
+	})
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/users/42"))
+	assert.NoError(t, err)
+	assert.Equal(t, "42", response.Body)
+
+	path, err := r.URL("user", map[string]string{"id": "7"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/users/7", path)
+}
+
+func TestNewRoute_placeholderErrorsReportPattern(t *testing.T) {
+	_, err := NewRoute(GET, "/users/:id/(unclosed", testHandler)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed compiling regex pattern '/users/:id/(unclosed'")
+
+	_, err = NewRoute(GET, "/users/:id$", testHandler)
+	assert.EqualError(t, err, "pattern '/users/:id$' must not contain '^' or '$' anchors, it is automatically anchored as '^/users/:id$/?$'")
+}
@@ -0,0 +1,99 @@
+// Package proxytest provides helpers for testing proxy routers, such as
+// building the api gateway v2 requests passed to Router.Route.
+package proxytest
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/prognoshealth/awsutils/proxy"
+)
+
+// RequestOption configures a request built by NewRequest.
+type RequestOption func(*events.APIGatewayV2HTTPRequest)
+
+// NewRequest returns an api gateway v2 request for the given method and path,
+// configured by the options.
+//
+// Example:
+//
+//	request := proxytest.NewRequest(proxy.POST, "/users",
+//		proxytest.WithHeader("Content-Type", "application/json"),
+//		proxytest.WithBody(`{"name":"yolo"}`),
+//	)
+//	response, err := router.Route(context.Background(), request)
+func NewRequest(method proxy.HttpMethod, path string, opts ...RequestOption) events.APIGatewayV2HTTPRequest {
+	request := events.APIGatewayV2HTTPRequest{
+		RawPath: path,
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method: method.String(),
+				Path:   path,
+			},
+		},
+		Headers: map[string]string{},
+	}
+
+	for _, opt := range opts {
+		opt(&request)
+	}
+
+	return request
+}
+
+// WithHeader sets the header on the request. The name is lowercased, as api
+// gateway v2 does, and repeated headers are comma joined.
+func WithHeader(name string, value string) RequestOption {
+	return func(request *events.APIGatewayV2HTTPRequest) {
+		name = strings.ToLower(name)
+
+		if existing, ok := request.Headers[name]; ok {
+			value = existing + "," + value
+		}
+
+		request.Headers[name] = value
+	}
+}
+
+// WithQuery sets the query string parameter on the request and updates the
+// RawQueryString. Repeated parameters are comma joined, as api gateway v2 does.
+func WithQuery(name string, value string) RequestOption {
+	return func(request *events.APIGatewayV2HTTPRequest) {
+		if request.QueryStringParameters == nil {
+			request.QueryStringParameters = map[string]string{}
+		}
+
+		if existing, ok := request.QueryStringParameters[name]; ok {
+			request.QueryStringParameters[name] = existing + "," + value
+		} else {
+			request.QueryStringParameters[name] = value
+		}
+
+		query := url.Values{}
+		if request.RawQueryString != "" {
+			query, _ = url.ParseQuery(request.RawQueryString)
+		}
+
+		query.Add(name, value)
+		request.RawQueryString = query.Encode()
+	}
+}
+
+// WithBody sets the plain text body of the request.
+func WithBody(body string) RequestOption {
+	return func(request *events.APIGatewayV2HTTPRequest) {
+		request.Body = body
+		request.IsBase64Encoded = false
+	}
+}
+
+// WithBase64Body sets the body of the request to the base64 encoded data, as
+// api gateway does for binary content.
+func WithBase64Body(data []byte) RequestOption {
+	return func(request *events.APIGatewayV2HTTPRequest) {
+		request.Body = base64.StdEncoding.EncodeToString(data)
+		request.IsBase64Encoded = true
+	}
+}
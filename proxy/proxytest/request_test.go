@@ -0,0 +1,91 @@
+package proxytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/prognoshealth/awsutils/proxy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRequest(t *testing.T) {
+	request := NewRequest(proxy.GET, "/users")
+
+	assert.Equal(t, "/users", request.RawPath)
+	assert.Equal(t, "GET", request.RequestContext.HTTP.Method)
+	assert.Equal(t, "/users", request.RequestContext.HTTP.Path)
+	assert.Equal(t, map[string]string{}, request.Headers)
+	assert.Nil(t, request.QueryStringParameters)
+	assert.Equal(t, "", request.Body)
+	assert.False(t, request.IsBase64Encoded)
+}
+
+func TestWithHeader(t *testing.T) {
+	request := NewRequest(proxy.GET, "/users",
+		WithHeader("Content-Type", "application/json"),
+		WithHeader("Accept", "text/plain"),
+		WithHeader("accept", "application/json"),
+	)
+
+	expected := map[string]string{
+		"content-type": "application/json",
+		"accept":       "text/plain,application/json",
+	}
+
+	assert.Equal(t, expected, request.Headers)
+}
+
+func TestWithQuery(t *testing.T) {
+	request := NewRequest(proxy.GET, "/users",
+		WithQuery("page", "2"),
+		WithQuery("tag", "a"),
+		WithQuery("tag", "b c"),
+	)
+
+	expected := map[string]string{
+		"page": "2",
+		"tag":  "a,b c",
+	}
+
+	assert.Equal(t, expected, request.QueryStringParameters)
+	assert.Equal(t, "page=2&tag=a&tag=b+c", request.RawQueryString)
+}
+
+func TestWithBody(t *testing.T) {
+	request := NewRequest(proxy.POST, "/users", WithBody("hey dude!"))
+
+	assert.Equal(t, "hey dude!", request.Body)
+	assert.False(t, request.IsBase64Encoded)
+}
+
+func TestWithBase64Body(t *testing.T) {
+	request := NewRequest(proxy.POST, "/users", WithBase64Body([]byte("hey dude!")))
+
+	assert.Equal(t, "aGV5IGR1ZGUh", request.Body)
+	assert.True(t, request.IsBase64Encoded)
+}
+
+func TestNewRequest_router(t *testing.T) {
+	r := &proxy.Router{}
+	r.POST("/users/(?P<id>[0-9]+)", func(ctx *proxy.RouteContext) (events.APIGatewayProxyResponse, error) {
+		body, err := ctx.Body()
+		if err != nil {
+			return events.APIGatewayProxyResponse{}, err
+		}
+
+		return proxy.Text(200, ctx.Params["id"]+" "+ctx.Params["page"]+" "+ctx.Header("x-test")+" "+body)
+	})
+
+	request := NewRequest(proxy.POST, "/users/42",
+		WithHeader("X-Test", "yolo"),
+		WithQuery("page", "2"),
+		WithBase64Body([]byte("hey dude!")),
+	)
+
+	response, err := r.Route(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, "42 2 yolo hey dude!", response.Body)
+}
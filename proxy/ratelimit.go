@@ -0,0 +1,146 @@
+package proxy
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// maxRateLimitKeys is the number of source ips tracked before buckets that
+// have fully refilled are pruned.
+const maxRateLimitKeys = 10000
+
+// rateLimiter is a token bucket rate limiter keyed by source ip.
+type rateLimiter struct {
+	mu       sync.Mutex
+	capacity float64
+	rate     float64 // tokens per second
+	buckets  map[string]*bucket
+	nowFunc  func() time.Time
+}
+
+// RateLimitOption configures the rate limit set with RateLimit
+type RateLimitOption func(*rateLimiter)
+
+// RateLimitClock sets the function used to get the current time when refilling
+// the buckets, e.g. to step through time in tests. It defaults to time.Now.
+func RateLimitClock(now func() time.Time) RateLimitOption {
+	return func(limiter *rateLimiter) {
+		limiter.nowFunc = now
+	}
+}
+
+// bucket holds the tokens available to a single source ip as of updated.
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// RateLimit limits each source ip, as given by RequestContext.HTTP.SourceIP,
+// to perMinute requests per minute using a token bucket, which allows bursts
+// of up to perMinute requests. Requests over the limit receive a 429 response
+// with a Retry-After header without a handler being executed. A perMinute of
+// zero or less removes the limit. The clock used to refill the buckets can be
+// set using RateLimitClock.
+//
+// The buckets are held in memory, so the limit is best effort and per lambda
+// instance. Concurrent instances each apply their own limit and a cold start
+// resets it, so this doesn't replace api gateway throttling or a shared store
+// when the limit must be enforced.
+func (router *Router) RateLimit(perMinute int, opts ...RateLimitOption) {
+	if perMinute <= 0 {
+		router.rateLimiter = nil
+		return
+	}
+
+	limiter := &rateLimiter{
+		capacity: float64(perMinute),
+		rate:     float64(perMinute) / 60,
+		buckets:  map[string]*bucket{},
+	}
+
+	for _, opt := range opts {
+		opt(limiter)
+	}
+
+	router.rateLimiter = limiter
+}
+
+// rateLimited returns a 429 response and true if the request's source ip has
+// exceeded the rate limit.
+func (router *Router) rateLimited(request events.APIGatewayV2HTTPRequest) (events.APIGatewayProxyResponse, bool) {
+	if router.rateLimiter == nil {
+		return events.APIGatewayProxyResponse{}, false
+	}
+
+	wait, ok := router.rateLimiter.take(request.RequestContext.HTTP.SourceIP, router.rateLimiter.now())
+	if ok {
+		return events.APIGatewayProxyResponse{}, false
+	}
+
+	response, _ := Text(429, "too many requests")
+	response.Headers["Retry-After"] = strconv.Itoa(int(math.Ceil(wait.Seconds())))
+
+	return response, true
+}
+
+// now returns the current time from the configured clock, see RateLimitClock
+func (limiter *rateLimiter) now() time.Time {
+	if limiter.nowFunc != nil {
+		return limiter.nowFunc()
+	}
+
+	return time.Now()
+}
+
+// take removes a token from the key's bucket and returns true, or returns
+// false and the time until a token is available if the bucket is empty.
+func (limiter *rateLimiter) take(key string, now time.Time) (time.Duration, bool) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	b, ok := limiter.buckets[key]
+	if !ok {
+		if len(limiter.buckets) >= maxRateLimitKeys {
+			limiter.prune(now)
+		}
+
+		b = &bucket{tokens: limiter.capacity, updated: now}
+		limiter.buckets[key] = b
+	}
+
+	limiter.refill(b, now)
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / limiter.rate * float64(time.Second))
+		return wait, false
+	}
+
+	b.tokens--
+
+	return 0, true
+}
+
+// refill adds the tokens accrued since the bucket was last updated, up to the
+// capacity.
+func (limiter *rateLimiter) refill(b *bucket, now time.Time) {
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = math.Min(limiter.capacity, b.tokens+elapsed.Seconds()*limiter.rate)
+		b.updated = now
+	}
+}
+
+// prune removes the buckets that have fully refilled, as they are equivalent
+// to a new bucket.
+func (limiter *rateLimiter) prune(now time.Time) {
+	for key, b := range limiter.buckets {
+		limiter.refill(b, now)
+
+		if b.tokens >= limiter.capacity {
+			delete(limiter.buckets, key)
+		}
+	}
+}
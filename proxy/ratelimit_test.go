@@ -0,0 +1,93 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func rateLimitRequest(ip string) events.APIGatewayV2HTTPRequest {
+	request := testRequest(GET, "/yolo")
+	request.RequestContext.HTTP.SourceIP = ip
+
+	return request
+}
+
+func TestRouter_RateLimit(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+
+	r := &Router{}
+	r.RateLimit(2, RateLimitClock(func() time.Time { return now }))
+	r.GET("/yolo", testHandler)
+
+	for i := 0; i < 2; i++ {
+		response, err := r.Route(context.Background(), rateLimitRequest("1.1.1.1"))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, response.StatusCode, fmt.Sprintf("request %d", i))
+	}
+
+	response, err := r.Route(context.Background(), rateLimitRequest("1.1.1.1"))
+	assert.NoError(t, err)
+	assert.Equal(t, 429, response.StatusCode)
+	assert.Equal(t, "30", response.Headers["Retry-After"])
+
+	// other source ips have their own bucket
+	response, _ = r.Route(context.Background(), rateLimitRequest("2.2.2.2"))
+	assert.Equal(t, 200, response.StatusCode)
+
+	// a token is refilled every 30 seconds at 2 per minute
+	now = now.Add(20 * time.Second)
+	response, _ = r.Route(context.Background(), rateLimitRequest("1.1.1.1"))
+	assert.Equal(t, 429, response.StatusCode)
+	assert.Equal(t, "10", response.Headers["Retry-After"])
+
+	now = now.Add(10 * time.Second)
+	response, _ = r.Route(context.Background(), rateLimitRequest("1.1.1.1"))
+	assert.Equal(t, 200, response.StatusCode)
+
+	response, _ = r.Route(context.Background(), rateLimitRequest("1.1.1.1"))
+	assert.Equal(t, 429, response.StatusCode)
+
+	// the bucket refills up to the limit only
+	now = now.Add(time.Hour)
+	for i := 0; i < 2; i++ {
+		response, _ = r.Route(context.Background(), rateLimitRequest("1.1.1.1"))
+		assert.Equal(t, 200, response.StatusCode)
+	}
+
+	response, _ = r.Route(context.Background(), rateLimitRequest("1.1.1.1"))
+	assert.Equal(t, 429, response.StatusCode)
+}
+
+func TestRouter_RateLimit_disabled(t *testing.T) {
+	r := &Router{}
+	r.RateLimit(1)
+	r.RateLimit(0)
+	r.GET("/yolo", testHandler)
+
+	for i := 0; i < 3; i++ {
+		response, _ := r.Route(context.Background(), rateLimitRequest("1.1.1.1"))
+		assert.Equal(t, 200, response.StatusCode)
+	}
+}
+
+func TestRateLimiter_prune(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+
+	r := &Router{}
+	r.RateLimit(60)
+
+	for i := 0; i < maxRateLimitKeys; i++ {
+		r.rateLimiter.take(fmt.Sprintf("10.0.%d.%d", i/256, i%256), now)
+	}
+
+	assert.Len(t, r.rateLimiter.buckets, maxRateLimitKeys)
+
+	_, ok := r.rateLimiter.take("1.1.1.1", now.Add(time.Second))
+	assert.True(t, ok)
+	assert.Len(t, r.rateLimiter.buckets, 1)
+}
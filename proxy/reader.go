@@ -0,0 +1,105 @@
+package proxy
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/pkg/errors"
+)
+
+// DefaultMaxResponseBytes is the default maximum body size read by Reader,
+// matching the 6MB lambda response payload limit.
+const DefaultMaxResponseBytes int64 = 6 * 1024 * 1024
+
+// SetMaxResponseBytes sets the maximum body size read by Router.Reader. Zero
+// or less restores the default, DefaultMaxResponseBytes.
+func (router *Router) SetMaxResponseBytes(n int64) {
+	router.maxResponseBytes = n
+}
+
+// maxResponseBytesOrDefault returns the configured maximum response body size
+func (router *Router) maxResponseBytesOrDefault() int64 {
+	if router.maxResponseBytes > 0 {
+		return router.maxResponseBytes
+	}
+
+	return DefaultMaxResponseBytes
+}
+
+// Reader is like the package level Reader but reads at most the router's
+// maximum response body size, see SetMaxResponseBytes.
+func (router *Router) Reader(statusCode int, contentType string, r io.Reader) (events.APIGatewayProxyResponse, error) {
+	return readResponse(statusCode, contentType, r, router.maxResponseBytesOrDefault())
+}
+
+// Reader returns a response with the given status code, content type and body
+// read from r. Textual content types, such as text/* or json, are returned as
+// is, other content types, or text that isn't valid utf-8, are base64 encoded
+// as required by api gateway for binary content.
+//
+// Api gateway and lambda can't stream a response, so the reader is read fully
+// into memory before the response is returned. If r holds more than
+// DefaultMaxResponseBytes a 500 response is returned with an error, use
+// Router.Reader for a different limit. If r implements io.Closer it is closed
+// once read.
+//
+// Example:
+//
+//	f, err := os.Open("report.csv")
+//	...
+//	return proxy.Reader(200, "text/csv", f)
+func Reader(statusCode int, contentType string, r io.Reader) (events.APIGatewayProxyResponse, error) {
+	return readResponse(statusCode, contentType, r, DefaultMaxResponseBytes)
+}
+
+// readResponse returns a response with the body read from r, failing if r holds
+// more than max bytes.
+func readResponse(statusCode int, contentType string, r io.Reader, max int64) (events.APIGatewayProxyResponse, error) {
+	if closer, ok := r.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, max+1))
+	if err != nil {
+		return events.APIGatewayProxyResponse{StatusCode: 500}, errors.Wrap(err, "failed reading response body")
+	}
+
+	if int64(len(data)) > max {
+		return events.APIGatewayProxyResponse{StatusCode: 500}, fmt.Errorf("response body exceeds the maximum of %d bytes", max)
+	}
+
+	if !isTextContentType(contentType) || !utf8.Valid(data) {
+		return Binary(statusCode, contentType, data)
+	}
+
+	response := events.APIGatewayProxyResponse{
+		StatusCode: statusCode,
+		Headers:    map[string]string{"Content-Type": contentType},
+		Body:       string(data),
+	}
+
+	return response, nil
+}
+
+// isTextContentType returns true if the content type is textual and can be
+// returned without base64 encoding.
+func isTextContentType(contentType string) bool {
+	mt := mediaType(contentType)
+
+	switch {
+	case strings.HasPrefix(mt, "text/"):
+		return true
+	case strings.HasSuffix(mt, "+json"), strings.HasSuffix(mt, "+xml"):
+		return true
+	}
+
+	switch mt {
+	case "application/json", "application/xml", "application/javascript", "application/x-www-form-urlencoded":
+		return true
+	}
+
+	return false
+}
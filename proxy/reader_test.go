@@ -0,0 +1,98 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("boom")
+}
+
+func TestReader_text(t *testing.T) {
+	r := &closeRecorder{Reader: strings.NewReader(`[{"id":1},{"id":2}]`)}
+	response, err := Reader(200, "application/json; charset=utf-8", r)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, "application/json; charset=utf-8", response.Headers["Content-Type"])
+	assert.Equal(t, `[{"id":1},{"id":2}]`, response.Body)
+	assert.False(t, response.IsBase64Encoded)
+	assert.True(t, r.closed)
+}
+
+func TestReader_binary(t *testing.T) {
+	data := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff, 0x10}
+	response, err := Reader(200, "image/png", bytes.NewReader(data))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", response.Headers["Content-Type"])
+	assert.Equal(t, base64.StdEncoding.EncodeToString(data), response.Body)
+	assert.True(t, response.IsBase64Encoded)
+
+	response, err = Reader(200, "text/plain", bytes.NewReader(data))
+
+	assert.NoError(t, err)
+	assert.True(t, response.IsBase64Encoded)
+}
+
+func TestRouter_Reader_tooLarge(t *testing.T) {
+	r := &Router{}
+	r.SetMaxResponseBytes(4)
+
+	response, err := r.Reader(200, "text/plain", strings.NewReader("four"))
+	assert.NoError(t, err)
+	assert.Equal(t, "four", response.Body)
+
+	response, err = r.Reader(200, "text/plain", strings.NewReader("fives"))
+	assert.EqualError(t, err, "response body exceeds the maximum of 4 bytes")
+	assert.Equal(t, 500, response.StatusCode)
+}
+
+func TestRouter_Reader_default(t *testing.T) {
+	r := &Router{}
+	assert.Equal(t, DefaultMaxResponseBytes, r.maxResponseBytesOrDefault())
+
+	r.SetMaxResponseBytes(4)
+	r.SetMaxResponseBytes(0)
+	assert.Equal(t, DefaultMaxResponseBytes, r.maxResponseBytesOrDefault())
+
+	response, err := r.Reader(200, "text/plain", strings.NewReader("fives"))
+	assert.NoError(t, err)
+	assert.Equal(t, "fives", response.Body)
+}
+
+func TestReader_error(t *testing.T) {
+	response, err := Reader(200, "text/plain", failingReader{})
+
+	assert.EqualError(t, err, "failed reading response body: boom")
+	assert.Equal(t, 500, response.StatusCode)
+}
+
+func TestIsTextContentType(t *testing.T) {
+	assert.True(t, isTextContentType("text/csv"))
+	assert.True(t, isTextContentType("Application/JSON"))
+	assert.True(t, isTextContentType("application/problem+json"))
+	assert.True(t, isTextContentType("application/xml; charset=utf-8"))
+	assert.False(t, isTextContentType("application/octet-stream"))
+	assert.False(t, isTextContentType("image/png"))
+	assert.False(t, isTextContentType(""))
+}
@@ -0,0 +1,68 @@
+package proxy
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/pkg/errors"
+)
+
+// JSON returns a response with the given status code and v marshalled as the
+// json body. If marshalling fails a 500 response is returned with the error.
+func JSON(statusCode int, v interface{}) (events.APIGatewayProxyResponse, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return events.APIGatewayProxyResponse{StatusCode: 500}, errors.Wrap(err, "failed marshalling json response")
+	}
+
+	response := events.APIGatewayProxyResponse{
+		StatusCode: statusCode,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       string(b),
+	}
+
+	return response, nil
+}
+
+// Text returns a plain text response with the given status code and body.
+func Text(statusCode int, body string) (events.APIGatewayProxyResponse, error) {
+	response := events.APIGatewayProxyResponse{
+		StatusCode: statusCode,
+		Headers:    map[string]string{"Content-Type": "text/plain; charset=utf-8"},
+		Body:       body,
+	}
+
+	return response, nil
+}
+
+// Binary returns a response with the given status code, content type and data
+// base64 encoded as the body, as required by api gateway for binary content
+// such as images or pdfs.
+func Binary(statusCode int, contentType string, data []byte) (events.APIGatewayProxyResponse, error) {
+	response := events.APIGatewayProxyResponse{
+		StatusCode:      statusCode,
+		Headers:         map[string]string{"Content-Type": contentType},
+		Body:            base64.StdEncoding.EncodeToString(data),
+		IsBase64Encoded: true,
+	}
+
+	return response, nil
+}
+
+// Redirect returns a redirect response with the given 3xx status code, the
+// Location header set and an empty body. If the status code isn't 3xx a 500
+// response is returned with an error.
+func Redirect(statusCode int, location string) (events.APIGatewayProxyResponse, error) {
+	if statusCode < 300 || statusCode > 399 {
+		return events.APIGatewayProxyResponse{StatusCode: 500}, fmt.Errorf("invalid redirect status code %d", statusCode)
+	}
+
+	response := events.APIGatewayProxyResponse{
+		StatusCode: statusCode,
+		Headers:    map[string]string{"Location": location},
+	}
+
+	return response, nil
+}
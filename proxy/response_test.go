@@ -0,0 +1,80 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSON(t *testing.T) {
+	response, err := JSON(201, map[string]string{"yolo": "it's true"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 201, response.StatusCode)
+	assert.Equal(t, "application/json", response.Headers["Content-Type"])
+	assert.Equal(t, `{"yolo":"it's true"}`, response.Body)
+	assert.False(t, response.IsBase64Encoded)
+}
+
+func TestJSON_error(t *testing.T) {
+	response, err := JSON(200, make(chan int))
+
+	assert.Error(t, err)
+	assert.Equal(t, 500, response.StatusCode)
+}
+
+func TestText(t *testing.T) {
+	response, err := Text(404, "not found")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 404, response.StatusCode)
+	assert.Equal(t, "text/plain; charset=utf-8", response.Headers["Content-Type"])
+	assert.Equal(t, "not found", response.Body)
+}
+
+func TestBinary(t *testing.T) {
+	data := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff, 0x10}
+	response, err := Binary(200, "image/png", data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, "image/png", response.Headers["Content-Type"])
+	assert.Equal(t, "iVBORwD/EA==", response.Body)
+	assert.True(t, response.IsBase64Encoded)
+}
+
+func TestBinary_roundTrip(t *testing.T) {
+	data := []byte{0x25, 0x50, 0x44, 0x46, 0x00, 0xe2, 0xe3, 0xcf, 0xd3}
+	response, err := Binary(200, "application/pdf", data)
+	assert.NoError(t, err)
+
+	request := testRequest(POST, "/upload")
+	request.Body = response.Body
+	request.IsBase64Encoded = response.IsBase64Encoded
+
+	b, err := newRouteContext(context.Background(), request, map[string]string{}).BodyBytes()
+	assert.NoError(t, err)
+	assert.Equal(t, data, b)
+}
+
+func TestRedirect(t *testing.T) {
+	for _, status := range []int{301, 302, 307, 308} {
+		response, err := Redirect(status, "https://example.com/yolo")
+
+		assert.NoError(t, err)
+		assert.Equal(t, status, response.StatusCode)
+		assert.Equal(t, map[string]string{"Location": "https://example.com/yolo"}, response.Headers)
+		assert.Empty(t, response.Body)
+	}
+}
+
+func TestRedirect_error(t *testing.T) {
+	for _, status := range []int{200, 299, 400, 404} {
+		response, err := Redirect(status, "/yolo")
+
+		assert.EqualError(t, err, fmt.Sprintf("invalid redirect status code %d", status))
+		assert.Equal(t, 500, response.StatusCode)
+	}
+}
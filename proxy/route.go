@@ -2,11 +2,9 @@ package proxy
 
 import (
 	"context"
-	"encoding/base64"
 	"fmt"
-	"net/url"
 	"regexp"
-	"strings"
+	"regexp/syntax"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/pkg/errors"
@@ -18,35 +16,186 @@ type RouteHandler func(*RouteContext) (events.APIGatewayProxyResponse, error)
 
 // Route defines a HttpMethod and Regex that are used in combination for
 // matching against an incoming request. When a match occurs the configured
-// handler is called.
+// handler is called, wrapped in the route's Middleware.
+//
+// Name optionally identifies the route for generating its path via Router.URL.
+//
+// ParamPrecedence optionally overrides the order, highest precedence first, in
+// which params are extracted, see Context.
+//
+// FormMethods optionally overrides the methods whose form body params are
+// extracted, see DefaultFormMethods.
+//
+// If MatchQuery is set the Regex is matched against the path followed by
+// '?<RawQueryString>' when the request has a query string, see NewQueryRoute.
 type Route struct {
-	Method  HttpMethod
-	Regex   *regexp.Regexp
-	Handler RouteHandler
+	Name            string
+	Method          HttpMethod
+	Regex           *regexp.Regexp
+	Handler         RouteHandler
+	Middleware      []Middleware
+	ParamPrecedence []ParamSource
+	FormMethods     []HttpMethod
+	MatchQuery      bool
 }
 
-// NewRoute returns a Route for the specified method, pattern and handler.
-func NewRoute(method HttpMethod, pattern string, handler RouteHandler) (*Route, error) {
-	rx, err := regexp.Compile("^" + pattern + "/?$")
+// NewRoute returns a Route for the specified method, pattern and handler. Any
+// middleware provided only wraps this route's handler.
+//
+// Path segments of the form ':name' and a trailing '*name' segment are expanded
+// into named capture groups, so '/files/:dir/*path' is equivalent to
+// '/files/(?P<dir>[^/]+)/(?P<path>.*)'. A colon anywhere other than the start
+// of a segment is literal, as is an escaped '\:name' segment.
+//
+// The pattern is automatically anchored as '^<pattern>/?$', so an error is
+// returned if it contains its own '^' or '$' anchors. Use NewRouteRaw for a
+// pattern without automatic anchoring.
+func NewRoute(method HttpMethod, pattern string, handler RouteHandler, middleware ...Middleware) (*Route, error) {
+	return newRoute(method, pattern, true, handler, middleware...)
+}
+
+// NewQueryRoute returns a Route like NewRoute that also matches against the
+// request's query string. The pattern is matched against '<path>?<query>', or
+// just the path when the request has no query string, so the pattern must
+// escape the '?' separator.
+//
+// Example:
+//
+//	// only matches searches that have a 'q' query parameter
+//	NewQueryRoute(GET, `/search\?(.*&)?q=.*`, searchHandler)
+func NewQueryRoute(method HttpMethod, pattern string, handler RouteHandler, middleware ...Middleware) (*Route, error) {
+	route, err := newRoute(method, pattern, true, handler, middleware...)
+	if err != nil {
+		return nil, err
+	}
+
+	route.MatchQuery = true
+
+	return route, nil
+}
+
+// NewRouteRaw returns a Route for the specified method, regex pattern and
+// handler. Unlike NewRoute the pattern is compiled as is, without automatic
+// anchoring or trailing slash handling, so it should usually provide its own
+// '^' and '$' anchors.
+func NewRouteRaw(method HttpMethod, pattern string, handler RouteHandler, middleware ...Middleware) (*Route, error) {
+	rx, err := regexp.Compile(pattern)
 
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed compiling regex pattern '%s'", pattern)
 	}
 
 	route := &Route{
-		Method:  method,
-		Regex:   rx,
-		Handler: handler,
+		Method:     method,
+		Regex:      rx,
+		Handler:    handler,
+		Middleware: middleware,
 	}
 
 	return route, nil
 }
 
+// newRoute returns a Route for the specified method, pattern and handler. If
+// trailingSlash is set the pattern also matches with an optional trailing
+// slash.
+func newRoute(method HttpMethod, pattern string, trailingSlash bool, handler RouteHandler, middleware ...Middleware) (*Route, error) {
+	suffix := "$"
+	if trailingSlash {
+		suffix = "/?$"
+	}
+
+	expanded := expandPlaceholders(pattern)
+
+	rx, err := regexp.Compile("^" + expanded + suffix)
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed compiling regex pattern '%s'", pattern)
+	}
+
+	if hasAnchor(expanded) {
+		return nil, fmt.Errorf("pattern '%s' must not contain '^' or '$' anchors, it is automatically anchored as '^%s%s'", pattern, pattern, suffix)
+	}
+
+	route := &Route{
+		Method:     method,
+		Regex:      rx,
+		Handler:    handler,
+		Middleware: middleware,
+	}
+
+	return route, nil
+}
+
+// hasAnchor returns true if the pattern contains an unescaped '^' or '$'
+// anchor outside of a character class.
+func hasAnchor(pattern string) bool {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return false
+	}
+
+	return containsAnchor(re)
+}
+
+// containsAnchor returns true if re or any of its sub expressions is a begin or
+// end anchor.
+func containsAnchor(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpBeginText, syntax.OpEndText, syntax.OpBeginLine, syntax.OpEndLine:
+		return true
+	}
+
+	for _, sub := range re.Sub {
+		if containsAnchor(sub) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // String returns a string representation of this route.
 func (route *Route) String() string {
 	return fmt.Sprintf("%s %s", route.Method, route.Regex)
 }
 
+// isCatchAll returns true if the route's pattern matches any path, such as the
+// pattern '.*'.
+func (route *Route) isCatchAll() bool {
+	re, err := syntax.Parse(route.Regex.String(), syntax.Perl)
+	if err != nil {
+		return false
+	}
+
+	subs := []*syntax.Regexp{re}
+	if re.Op == syntax.OpConcat {
+		subs = re.Sub
+	}
+
+	wild := 0
+
+	for _, sub := range subs {
+		switch {
+		case sub.Op == syntax.OpBeginText || sub.Op == syntax.OpEndText:
+			continue
+		case sub.Op == syntax.OpQuest && sub.Sub[0].Op == syntax.OpLiteral && string(sub.Sub[0].Rune) == "/":
+			continue
+		}
+
+		for sub.Op == syntax.OpCapture {
+			sub = sub.Sub[0]
+		}
+
+		if sub.Op != syntax.OpStar || (sub.Sub[0].Op != syntax.OpAnyChar && sub.Sub[0].Op != syntax.OpAnyCharNotNL) {
+			return false
+		}
+
+		wild++
+	}
+
+	return wild > 0
+}
+
 // IsMatch return true if there is a match otherwise false. The match groups are
 // also returned.
 func (route *Route) IsMatch(request events.APIGatewayV2HTTPRequest) (bool, []string) {
@@ -54,7 +203,7 @@ func (route *Route) IsMatch(request events.APIGatewayV2HTTPRequest) (bool, []str
 		return false, nil
 	}
 
-	groups := route.Regex.FindStringSubmatch(request.RawPath)
+	groups := route.Regex.FindStringSubmatch(route.matchTarget(request))
 
 	if len(groups) == 0 {
 		return false, nil
@@ -63,6 +212,16 @@ func (route *Route) IsMatch(request events.APIGatewayV2HTTPRequest) (bool, []str
 	return true, groups
 }
 
+// matchTarget returns the part of the request the route's regex is matched
+// against, the path and, if MatchQuery is set, the query string.
+func (route *Route) matchTarget(request events.APIGatewayV2HTTPRequest) string {
+	if route.MatchQuery && request.RawQueryString != "" {
+		return request.RawPath + "?" + request.RawQueryString
+	}
+
+	return request.RawPath
+}
+
 // extractParamsFromPath pulls the paramenters set on the aws api gateway path
 // parameters.
 func (route *Route) extractParamsFromPath(params map[string]string, request events.APIGatewayV2HTTPRequest) {
@@ -88,45 +247,32 @@ func (route *Route) extractParamsFromURIRegex(params map[string]string, groups [
 	}
 }
 
-// extractParamsFromFormPost extracts the params from a POSTed body with content
-// type 'application/x-www-form-urlencoded'.
+// extractParamsFromFormPost extracts the params from a body with content type
+// 'application/x-www-form-urlencoded' for the route's form methods. The content
+// type header name and media type are matched case insensitively and parameters
+// such as charset are ignored.
 func (route *Route) extractParamsFromFormPost(params map[string]string, request events.APIGatewayV2HTTPRequest) error {
-	if POST.String() != request.RequestContext.HTTP.Method {
+	if !route.formMethod(request.RequestContext.HTTP.Method) {
 		return nil
 	}
 
-	if request.Headers["content-type"] != "application/x-www-form-urlencoded" {
+	values := headerValues(request.Headers, "content-type")
+	if len(values) == 0 || mediaType(values[0]) != "application/x-www-form-urlencoded" {
 		return nil
 	}
 
-	body := ""
-
-	if request.IsBase64Encoded {
-		b, err := base64.StdEncoding.DecodeString(request.Body)
-		if err != nil {
-			return errors.Wrapf(err, "unable to decode request form params %v", request)
-		}
-
-		body = string(b)
-	} else {
-		body = request.Body
+	body, err := decodedBody(request)
+	if err != nil {
+		return err
 	}
 
-	kvs := strings.Split(body, "&")
-
-	for _, kv := range kvs {
-		kvSplit := strings.Split(kv, "=")
-
-		if len(kvSplit) != 2 {
-			return fmt.Errorf("invalid key/value pair in form post for %v", request)
-		}
-
-		v, err := url.QueryUnescape(kvSplit[1])
-		if err != nil {
-			return errors.Wrapf(err, "unable to decode value '%v'", kvSplit[1])
-		}
+	form, err := parseURLEncodedForm(string(body))
+	if err != nil {
+		return errors.Wrapf(err, "failed parsing form post for %v", request)
+	}
 
-		params[kvSplit[0]] = v
+	for k, v := range form {
+		params[k] = v[len(v)-1]
 	}
 
 	return nil
@@ -136,40 +282,50 @@ func (route *Route) extractParamsFromFormPost(params map[string]string, request
 // The 'Params' that get set on the context are extracted from the request with
 // the following precedence:
 //
-//	1) Form POSTs
+//	1) Form POST, PUT and PATCH bodies
 //  2) Route defined regex capture
 //  3) Query string
 //  4) AWS API Gateway configured PathParameters.
+//
+// The precedence can be changed by setting ParamPrecedence.
 func (route *Route) Context(ctx context.Context, request events.APIGatewayV2HTTPRequest, groups []string) (*RouteContext, error) {
 	if len(groups) == 0 {
 		return nil, fmt.Errorf("No matches available, unabled to generate context for route %v", route)
 	}
 
-	params := make(map[string]string)
-	route.extractParamsFromPath(params, request)
-	route.extractParamsFromQueryString(params, request)
-	route.extractParamsFromURIRegex(params, groups)
-	err := route.extractParamsFromFormPost(params, request)
+	params, err := route.extractParams(request, groups)
 
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed extractParamsFromFormPost")
+		return nil, err
 	}
 
-	return &RouteContext{
-		Context: ctx,
-		Request: request,
-		Params:  params,
-	}, nil
+	rctx := newRouteContext(ctx, request, params)
+	rctx.MatchedRoute = route
+	rctx.MatchedPath = groups[0]
+
+	return rctx, nil
 }
 
 // Follow extracts the route context for the given request and executed the
 // route's handler function.
 func (route *Route) Follow(ctx context.Context, request events.APIGatewayV2HTTPRequest, groups []string) (events.APIGatewayProxyResponse, error) {
+	response, _, err := route.follow(ctx, request, groups, nil)
+	return response, err
+}
+
+// follow extracts the route context for the given request and executes the
+// route's handler. The provided middleware runs first, followed by the route's
+// own middleware and finally the handler. The route context is returned, or nil
+// if it couldn't be extracted.
+func (route *Route) follow(ctx context.Context, request events.APIGatewayV2HTTPRequest, groups []string, middleware []Middleware) (events.APIGatewayProxyResponse, *RouteContext, error) {
 	rctx, err := route.Context(ctx, request, groups)
 
 	if err != nil {
-		return events.APIGatewayProxyResponse{}, errors.Wrapf(err, "failed getting context for route %v", route.Regex)
+		return events.APIGatewayProxyResponse{}, nil, errors.Wrapf(err, "failed getting context for route %v", route.Regex)
 	}
 
-	return route.Handler(rctx)
+	handler := chain(route.Handler, route.Middleware...)
+	response, err := chain(handler, middleware...)(rctx)
+
+	return response, rctx, err
 }
@@ -17,11 +17,94 @@ func TestNewRoute(t *testing.T) {
 	assert.NotNil(t, r.Handler)
 }
 
+func TestNewRoute_middleware(t *testing.T) {
+	calls := []string{}
+
+	r, err := NewRoute(GET, "/yolo", testHandler, appendMiddleware("first", &calls), appendMiddleware("second", &calls))
+	assert.NoError(t, err)
+	assert.Len(t, r.Middleware, 2)
+
+	request := testRequest(GET, "/yolo")
+	matched, groups := r.IsMatch(request)
+	assert.True(t, matched)
+
+	response, err := r.Follow(context.Background(), request, groups)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, []string{"first", "second"}, calls)
+}
+
 func TestNewRoute_Error(t *testing.T) {
 	_, err := NewRoute(GET, "asom (?<in-invalid>.*)", testHandler)
 	assert.Error(t, err)
 }
 
+func TestNewRoute_anchors(t *testing.T) {
+	_, err := NewRoute(GET, "/yolo$", testHandler)
+	assert.EqualError(t, err, "pattern '/yolo$' must not contain '^' or '$' anchors, it is automatically anchored as '^/yolo$/?$'")
+
+	_, err = NewRoute(GET, "^/yolo", testHandler)
+	assert.Error(t, err)
+
+	_, err = NewRoute(GET, "/(a|b$)", testHandler)
+	assert.Error(t, err)
+
+	r, err := NewRoute(GET, `/price/\$[0-9]+`, testHandler)
+	assert.NoError(t, err)
+	assert.True(t, r.Regex.MatchString("/price/$42"))
+
+	r, err = NewRoute(GET, "/chars/[$^]", testHandler)
+	assert.NoError(t, err)
+	assert.True(t, r.Regex.MatchString("/chars/$"))
+	assert.True(t, r.Regex.MatchString("/chars/^"))
+}
+
+func TestNewRoute_anchorsRouter(t *testing.T) {
+	r := &Router{}
+	r.GET("/yolo$", testHandler)
+
+	assert.False(t, r.Valid())
+	assert.Empty(t, r.Routes)
+}
+
+func TestNewRouteRaw(t *testing.T) {
+	r, err := NewRouteRaw(GET, "^/yolo$", testHandler)
+	assert.NoError(t, err)
+
+	assert.Equal(t, GET, r.Method)
+	assert.True(t, r.Regex.MatchString("/yolo"))
+	assert.False(t, r.Regex.MatchString("/yolo/"))
+
+	r, err = NewRouteRaw(GET, "/yolo", testHandler)
+	assert.NoError(t, err)
+	assert.True(t, r.Regex.MatchString("/api/yolo/more"))
+
+	_, err = NewRouteRaw(GET, "asom (?<in-invalid>.*)", testHandler)
+	assert.Error(t, err)
+}
+
+func TestRoute_isCatchAll(t *testing.T) {
+	cases := []struct {
+		pattern  string
+		expected bool
+	}{
+		{".*", true},
+		{"(.*)", true},
+		{"(?P<all>.*)", true},
+		{"(?s).*", true},
+		{".+", false},
+		{"/.*", false},
+		{"/yolo", false},
+		{"/yolo/(?P<id>[^/]+)", false},
+	}
+
+	for _, c := range cases {
+		r, err := NewRoute(GET, c.pattern, testHandler)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, r.isCatchAll(), c.pattern)
+	}
+}
+
 func TestRoute_Match(t *testing.T) {
 	r, err := NewRoute(GET, "/yolo", testHandler)
 	assert.NoError(t, err)
@@ -104,6 +187,7 @@ func TestRoute_Context(t *testing.T) {
 	assert.Equal(t, ctx, rctx.Context)
 	assert.Equal(t, request, rctx.Request)
 	assert.Empty(t, rctx.Params)
+	assert.Equal(t, r, rctx.MatchedRoute)
 }
 
 func TestRoute_Context_wild(t *testing.T) {
@@ -187,7 +271,7 @@ func TestRoute_Context_params_query(t *testing.T) {
 	}
 
 	assert.NoError(t, err)
-	assert.Equal(t, ctx, rctx.Context)
+	assert.Equal(t, context.WithValue(ctx, RequestIDKey, "PxxoehGYCYcDJdg="), rctx.Context)
 	assert.Equal(t, request, rctx.Request)
 	assert.Equal(t, expected, rctx.Params)
 }
@@ -210,7 +294,7 @@ func TestRoute_Context_params_form(t *testing.T) {
 	}
 
 	assert.NoError(t, err)
-	assert.Equal(t, ctx, rctx.Context)
+	assert.Equal(t, context.WithValue(ctx, RequestIDKey, "PxxoehGYCYcDJdg="), rctx.Context)
 	assert.Equal(t, request, rctx.Request)
 	assert.Equal(t, expected, rctx.Params)
 }
@@ -233,7 +317,7 @@ func TestRoute_Context_params_awspath(t *testing.T) {
 	}
 
 	assert.NoError(t, err)
-	assert.Equal(t, ctx, rctx.Context)
+	assert.Equal(t, context.WithValue(ctx, RequestIDKey, "PxxoehGYCYcDJdg="), rctx.Context)
 	assert.Equal(t, request, rctx.Request)
 	assert.Equal(t, expected, rctx.Params)
 }
@@ -258,7 +342,7 @@ func TestRoute_Context_params_multi(t *testing.T) {
 	}
 
 	assert.NoError(t, err)
-	assert.Equal(t, ctx, rctx.Context)
+	assert.Equal(t, context.WithValue(ctx, RequestIDKey, "PxxoehGYCYcDJdg="), rctx.Context)
 	assert.Equal(t, request, rctx.Request)
 	assert.Equal(t, expected, rctx.Params)
 }
@@ -337,6 +421,103 @@ func TestRoute_extractParamsFromFormPost_nobase64(t *testing.T) {
 	assert.Equal(t, expected, params)
 }
 
+func TestRoute_extractParamsFromFormPost_charset(t *testing.T) {
+	r, err := NewRoute(POST, "/hi", testHandler)
+	assert.NoError(t, err)
+
+	request := testRequest(POST, "/hi")
+	request.Headers["content-type"] = "application/x-www-form-urlencoded; charset=UTF-8"
+	request.Body = "super=red+sonya&die=hard"
+
+	params := map[string]string{}
+	expected := map[string]string{
+		"super": "red sonya",
+		"die":   "hard",
+	}
+
+	err = r.extractParamsFromFormPost(params, request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, params)
+}
+
+func TestRoute_extractParamsFromFormPost_uppercaseHeader(t *testing.T) {
+	r, err := NewRoute(POST, "/hi", testHandler)
+	assert.NoError(t, err)
+
+	request := testRequest(POST, "/hi")
+	request.Headers = map[string]string{"Content-Type": "Application/X-WWW-Form-Urlencoded"}
+	request.Body = "super=red+sonya&die=hard"
+
+	params := map[string]string{}
+	expected := map[string]string{
+		"super": "red sonya",
+		"die":   "hard",
+	}
+
+	err = r.extractParamsFromFormPost(params, request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, params)
+}
+
+func TestRoute_extractParamsFromFormPost_equalsInValue(t *testing.T) {
+	r, err := NewRoute(POST, "/hi", testHandler)
+	assert.NoError(t, err)
+
+	request := testRequest(POST, "/hi")
+	request.Headers["content-type"] = "application/x-www-form-urlencoded"
+	request.Body = "token=abc==&eq=a=b&empty="
+
+	params := map[string]string{}
+	expected := map[string]string{
+		"token": "abc==",
+		"eq":    "a=b",
+		"empty": "",
+	}
+
+	err = r.extractParamsFromFormPost(params, request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, params)
+}
+
+func TestRoute_extractParamsFromFormPost_escapedKey(t *testing.T) {
+	r, err := NewRoute(POST, "/hi", testHandler)
+	assert.NoError(t, err)
+
+	request := testRequest(POST, "/hi")
+	request.Headers["content-type"] = "application/x-www-form-urlencoded"
+	request.Body = "first+name=jeff&items%5B%5D=one"
+
+	params := map[string]string{}
+	expected := map[string]string{
+		"first name": "jeff",
+		"items[]":    "one",
+	}
+
+	err = r.extractParamsFromFormPost(params, request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, params)
+}
+
+func TestRoute_extractParamsFromFormPost_error_encodeKey(t *testing.T) {
+	r, err := NewRoute(POST, "/hi", testHandler)
+	assert.NoError(t, err)
+
+	request := testRequest(POST, "/hi")
+	request.Headers["content-type"] = "application/x-www-form-urlencoded"
+	request.Body = "as%Zdfg=hi"
+
+	params := map[string]string{}
+
+	err = r.extractParamsFromFormPost(params, request)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to decode key")
+}
+
 func TestRoute_extractParamsFromFormPost_error_base64(t *testing.T) {
 	r, err := NewRoute(POST, "/hi", testHandler)
 	assert.NoError(t, err)
@@ -354,6 +535,22 @@ func TestRoute_extractParamsFromFormPost_error_base64(t *testing.T) {
 	assert.Contains(t, err.Error(), "illegal base64 data")
 }
 
+func TestRoute_extractParamsFromFormPost_error_base64MatchesBody(t *testing.T) {
+	r, err := NewRoute(POST, "/hi", testHandler)
+	assert.NoError(t, err)
+
+	request := testRequest(POST, "/hi")
+	request.Headers["content-type"] = "application/x-www-form-urlencoded"
+	request.IsBase64Encoded = true
+	request.Body = "eW9sbz1ka****=="
+
+	formErr := r.extractParamsFromFormPost(map[string]string{}, request)
+	_, bodyErr := (&RouteContext{Request: request}).Body()
+
+	assert.EqualError(t, formErr, "unable to decode base64 request body: illegal base64 data at input byte 9")
+	assert.EqualError(t, bodyErr, formErr.Error())
+}
+
 func TestRoute_extractParamsFromFormPost_error_form(t *testing.T) {
 	r, err := NewRoute(POST, "/hi", testHandler)
 	assert.NoError(t, err)
@@ -414,3 +611,69 @@ func TestRoute_Follow_error(t *testing.T) {
 	_, err = r.Follow(ctx, request, []string{})
 	assert.Error(t, err)
 }
+
+func TestNewQueryRoute(t *testing.T) {
+	r, err := NewQueryRoute(GET, `/search\?(.*&)?q=(?P<q>[^&]*).*`, testHandler)
+	assert.NoError(t, err)
+	assert.True(t, r.MatchQuery)
+
+	request := testRequest(GET, "/search")
+	request.RawQueryString = "page=2&q=yolo"
+
+	matched, groups := r.IsMatch(request)
+	assert.True(t, matched)
+
+	ctx, err := r.Context(context.Background(), request, groups)
+	assert.NoError(t, err)
+	assert.Equal(t, "yolo", ctx.Params["q"])
+
+	request.RawQueryString = "page=2"
+	matched, _ = r.IsMatch(request)
+	assert.False(t, matched)
+
+	request.RawQueryString = ""
+	matched, _ = r.IsMatch(request)
+	assert.False(t, matched)
+}
+
+func TestNewQueryRoute_error(t *testing.T) {
+	_, err := NewQueryRoute(GET, "^/search", testHandler)
+	assert.Error(t, err)
+}
+
+func TestRoute_IsMatch_ignoresQuery(t *testing.T) {
+	r, err := NewRoute(GET, "/search", testHandler)
+	assert.NoError(t, err)
+
+	request := testRequest(GET, "/search")
+	request.RawQueryString = "q=yolo"
+
+	matched, _ := r.IsMatch(request)
+	assert.True(t, matched)
+}
+
+func TestRoute_Context_matchedPath(t *testing.T) {
+	r, err := NewRoute(GET, ".*", testHandler)
+	assert.NoError(t, err)
+
+	request := testRequest(GET, "/any/path/at/all")
+	_, groups := r.IsMatch(request)
+
+	ctx, err := r.Context(context.Background(), request, groups)
+	assert.NoError(t, err)
+	assert.Equal(t, "/any/path/at/all", ctx.MatchedPath)
+	assert.Empty(t, ctx.Params)
+}
+
+func TestRoute_Context_matchedPathQuery(t *testing.T) {
+	r, err := NewQueryRoute(GET, `/search\?.*`, testHandler)
+	assert.NoError(t, err)
+
+	request := testRequest(GET, "/search")
+	request.RawQueryString = "q=yolo"
+	_, groups := r.IsMatch(request)
+
+	ctx, err := r.Context(context.Background(), request, groups)
+	assert.NoError(t, err)
+	assert.Equal(t, "/search?q=yolo", ctx.MatchedPath)
+}
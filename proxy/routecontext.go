@@ -3,28 +3,295 @@ package proxy
 import (
 	"context"
 	"encoding/base64"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/pkg/errors"
 )
 
+// ContextKey is the type of the keys the router uses to store values in the
+// handler's context.Context.
+type ContextKey string
+
+// RequestIDKey is the context.Context key the api gateway request id is stored
+// under.
+const RequestIDKey ContextKey = "proxy-request-id"
+
 // RouteContext contains all the request information for a route when matched.
+//
+// RequestID is the api gateway request id, which is also stored in Context
+// under RequestIDKey.
+//
+// MatchedRoute is the route being followed, e.g. for logging the matched
+// pattern rather than the concrete path. It is nil for the CatchAll handler.
+//
+// MatchedPath is the full string matched by the route's pattern, the request
+// path or, for a route with MatchQuery set, the path and query string. It is
+// available even when the pattern has no capture groups, such as the catch all
+// route '.*', which helps generic proxy handlers. It is empty for the CatchAll
+// handler.
 type RouteContext struct {
-	Context context.Context
-	Request events.APIGatewayV2HTTPRequest
-	Params  map[string]string
+	Context      context.Context
+	Request      events.APIGatewayV2HTTPRequest
+	Params       map[string]string
+	RequestID    string
+	MatchedRoute *Route
+	MatchedPath  string
+}
+
+// newRouteContext returns a RouteContext for the request with the request id
+// injected into the context.
+func newRouteContext(ctx context.Context, request events.APIGatewayV2HTTPRequest, params map[string]string) *RouteContext {
+	id := request.RequestContext.RequestID
+	if id != "" {
+		ctx = context.WithValue(ctx, RequestIDKey, id)
+	}
+
+	return &RouteContext{
+		Context:   ctx,
+		Request:   request,
+		Params:    params,
+		RequestID: id,
+	}
+}
+
+// RequestIDFromContext returns the api gateway request id stored in the
+// context by the router, and whether it was present.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(RequestIDKey).(string)
+	return id, ok
 }
 
 // Body returns a string representation of the request body
 func (ctx *RouteContext) Body() (string, error) {
-	if ctx.Request.IsBase64Encoded {
-		b, err := base64.StdEncoding.DecodeString(ctx.Request.Body)
-		if err != nil {
-			return "", errors.Wrapf(err, "unable to decode request body for request %v", ctx.Request)
+	b, err := ctx.BodyBytes()
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// DecodedBody returns a string representation of the request body along with
+// whether it was base64 decoded, which helps when debugging encoding issues.
+func (ctx *RouteContext) DecodedBody() (body string, wasBase64 bool, err error) {
+	body, err = ctx.Body()
+	return body, ctx.Request.IsBase64Encoded, err
+}
+
+// BodyBytes returns the raw request body, base64 decoding it if required, such
+// as for binary uploads.
+func (ctx *RouteContext) BodyBytes() ([]byte, error) {
+	return decodedBody(ctx.Request)
+}
+
+// decodedBody returns the request body, base64 decoding it if required. Every
+// reader of the request body uses it so decoding and its error are consistent.
+func decodedBody(request events.APIGatewayV2HTTPRequest) ([]byte, error) {
+	if !request.IsBase64Encoded {
+		return []byte(request.Body), nil
+	}
+
+	b, err := base64.StdEncoding.DecodeString(request.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to decode base64 request body")
+	}
+
+	return b, nil
+}
+
+// Param is a single param name and value, see SortedParams.
+type Param struct {
+	Key   string
+	Value string
+}
+
+// SortedParams returns the params sorted by key, for deterministic logging and
+// test assertions as Params is a map.
+func (ctx *RouteContext) SortedParams() []Param {
+	params := make([]Param, 0, len(ctx.Params))
+
+	for k, v := range ctx.Params {
+		params = append(params, Param{Key: k, Value: v})
+	}
+
+	sort.Slice(params, func(i, j int) bool {
+		return params[i].Key < params[j].Key
+	})
+
+	return params
+}
+
+// ParamString returns the named param and whether it is present.
+func (ctx *RouteContext) ParamString(name string) (string, bool) {
+	v, ok := ctx.Params[name]
+	return v, ok
+}
+
+// param returns the named param or an error if it is missing.
+func (ctx *RouteContext) param(name string) (string, error) {
+	v, ok := ctx.ParamString(name)
+	if !ok {
+		return "", fmt.Errorf("param '%s' is missing", name)
+	}
+
+	return v, nil
+}
+
+// ParamInt returns the named param parsed as an int.
+func (ctx *RouteContext) ParamInt(name string) (int, error) {
+	v, err := ctx.param(name)
+	if err != nil {
+		return 0, err
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, errors.Wrapf(err, "param '%s' is not an int", name)
+	}
+
+	return i, nil
+}
+
+// ParamInt64 returns the named param parsed as an int64.
+func (ctx *RouteContext) ParamInt64(name string) (int64, error) {
+	v, err := ctx.param(name)
+	if err != nil {
+		return 0, err
+	}
+
+	i, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "param '%s' is not an int64", name)
+	}
+
+	return i, nil
+}
+
+// ParamBool returns the named param parsed as a bool. Accepted values are those
+// of strconv.ParseBool.
+func (ctx *RouteContext) ParamBool(name string) (bool, error) {
+	v, err := ctx.param(name)
+	if err != nil {
+		return false, err
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, errors.Wrapf(err, "param '%s' is not a bool", name)
+	}
+
+	return b, nil
+}
+
+// Headers returns all the values of the named request header. The lookup is
+// case insensitive as api gateway v2 lowercases header names. Repeated headers
+// are comma joined by api gateway and are split back into separate values.
+func (ctx *RouteContext) Headers(name string) []string {
+	return headerValues(ctx.Request.Headers, name)
+}
+
+// headerValues returns all the values of the named header, see Headers.
+func headerValues(headers map[string]string, name string) []string {
+	for k, v := range headers {
+		if !strings.EqualFold(k, name) {
+			continue
+		}
+
+		values := strings.Split(v, ",")
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
 		}
 
-		return string(b), nil
+		return values
+	}
+
+	return nil
+}
+
+// Header returns the first value of the named request header or an empty
+// string if it is not present. See Headers for lookup details.
+func (ctx *RouteContext) Header(name string) string {
+	values := ctx.Headers(name)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// SourceIP returns the ip address of the caller. If the request has an
+// X-Forwarded-For header, of the form 'client, proxy1, proxy2', its first entry
+// is returned as the original client, provided it is a valid ip address.
+// Otherwise the RequestContext.HTTP.SourceIP, the address of the immediate
+// caller, is returned.
+//
+// The X-Forwarded-For header is set by the client or intermediate proxies, so
+// it can be spoofed and shouldn't be used for access control.
+func (ctx *RouteContext) SourceIP() string {
+	forwarded := ctx.Header("x-forwarded-for")
+	if net.ParseIP(forwarded) != nil {
+		return forwarded
+	}
+
+	return ctx.Request.RequestContext.HTTP.SourceIP
+}
+
+// UserAgent returns the request's User-Agent header, falling back to the
+// RequestContext.HTTP.UserAgent.
+func (ctx *RouteContext) UserAgent() string {
+	for k, v := range ctx.Request.Headers {
+		if strings.EqualFold(k, "user-agent") {
+			return v
+		}
+	}
+
+	return ctx.Request.RequestContext.HTTP.UserAgent
+}
+
+// BearerToken returns the token of the request's 'Authorization: Bearer
+// <token>' header. The scheme is matched case insensitively. False is returned
+// if the header is missing, empty or uses another scheme.
+func (ctx *RouteContext) BearerToken() (string, bool) {
+	return ctx.authorization("bearer")
+}
+
+// authorization returns the credentials of the request's Authorization header
+// if its scheme matches, case insensitively, the given scheme.
+func (ctx *RouteContext) authorization(scheme string) (string, bool) {
+	for k, v := range ctx.Request.Headers {
+		if !strings.EqualFold(k, "authorization") {
+			continue
+		}
+
+		name, credentials, ok := strings.Cut(strings.TrimSpace(v), " ")
+		if !ok || !strings.EqualFold(name, scheme) {
+			return "", false
+		}
+
+		credentials = strings.TrimSpace(credentials)
+
+		return credentials, credentials != ""
+	}
+
+	return "", false
+}
+
+// QueryAll returns all the values of the named query string parameter. Api
+// gateway v2 comma joins repeated parameters, so '?tag=a&tag=b' yields
+// []string{"a", "b"}. Params only holds the joined single value.
+//
+// A comma within a single value can't be distinguished from a repeated
+// parameter, so '?tag=a,b' also yields []string{"a", "b"}.
+func (ctx *RouteContext) QueryAll(name string) []string {
+	v, ok := ctx.Request.QueryStringParameters[name]
+	if !ok {
+		return nil
 	}
 
-	return ctx.Request.Body, nil
+	return strings.Split(v, ",")
 }
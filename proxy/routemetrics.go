@@ -0,0 +1,71 @@
+package proxy
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// RouteMetrics describes how long routing a single request took.
+//
+// MatchDuration is the time spent finding the matching route and
+// HandlerDuration the time spent executing the matched route's middleware and
+// handler. MatchedPattern is the regex pattern of the matched route, and is
+// empty, as is HandlerDuration, when no route matched.
+type RouteMetrics struct {
+	MatchDuration   time.Duration
+	HandlerDuration time.Duration
+	MatchedPattern  string
+}
+
+// RouteWithMetrics routes the request exactly like Route and also returns the
+// timing of matching and handling the request. Unlike SetLogger and
+// EnableEMFMetrics nothing is emitted, the caller decides what to do with the
+// metrics.
+//
+// Example:
+//
+//	response, metrics, err := router.RouteWithMetrics(ctx, request)
+//	if metrics.MatchDuration > 10*time.Millisecond {
+//		log.Printf("slow match for %s: %v", metrics.MatchedPattern, metrics.MatchDuration)
+//	}
+func (router *Router) RouteWithMetrics(ctx context.Context, request events.APIGatewayV2HTTPRequest) (events.APIGatewayProxyResponse, RouteMetrics, error) {
+	metrics := RouteMetrics{}
+	response, err := router.route(ctx, request, &metrics)
+
+	return response, metrics, err
+}
+
+// mark returns the current time if metrics are being recorded, so routing
+// without metrics doesn't read the clock.
+func (metrics *RouteMetrics) mark(router *Router) time.Time {
+	if metrics == nil {
+		return time.Time{}
+	}
+
+	return router.now()
+}
+
+// recordMatch records the time since mark as the match duration, along with
+// the pattern of the matched route if any.
+func (metrics *RouteMetrics) recordMatch(router *Router, mark time.Time, route *Route) {
+	if metrics == nil {
+		return
+	}
+
+	metrics.MatchDuration = router.now().Sub(mark)
+
+	if route != nil {
+		metrics.MatchedPattern = route.Regex.String()
+	}
+}
+
+// recordHandler records the time since mark as the handler duration.
+func (metrics *RouteMetrics) recordHandler(router *Router, mark time.Time) {
+	if metrics == nil {
+		return
+	}
+
+	metrics.HandlerDuration = router.now().Sub(mark)
+}
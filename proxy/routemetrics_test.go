@@ -0,0 +1,58 @@
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter_RouteWithMetrics(t *testing.T) {
+	r := &Router{}
+	r.nowFunc = stepClock(5 * time.Millisecond)
+	r.GET("/route/(?P<id>[0-9]+)", testHandler)
+
+	response, metrics, err := r.RouteWithMetrics(context.Background(), testRequest(GET, "/route/4"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+
+	expected := RouteMetrics{
+		MatchDuration:   5 * time.Millisecond,
+		HandlerDuration: 5 * time.Millisecond,
+		MatchedPattern:  "^/route/(?P<id>[0-9]+)/?$",
+	}
+
+	assert.Equal(t, expected, metrics)
+}
+
+func TestRouter_RouteWithMetrics_handlerDuration(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+
+	r := &Router{}
+	r.nowFunc = func() time.Time { return now }
+	r.GET("/slow", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		now = now.Add(time.Second)
+		return Text(200, "done")
+	})
+
+	_, metrics, err := r.RouteWithMetrics(context.Background(), testRequest(GET, "/slow"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), metrics.MatchDuration)
+	assert.Equal(t, time.Second, metrics.HandlerDuration)
+	assert.Equal(t, "^/slow/?$", metrics.MatchedPattern)
+}
+
+func TestRouter_RouteWithMetrics_notFound(t *testing.T) {
+	r := &Router{}
+	r.nowFunc = stepClock(time.Millisecond)
+	r.GET("/yolo", testHandler)
+
+	_, metrics, err := r.RouteWithMetrics(context.Background(), testRequest(GET, "/nope"))
+
+	assert.Error(t, err)
+	assert.Equal(t, RouteMetrics{MatchDuration: time.Millisecond}, metrics)
+}
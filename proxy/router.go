@@ -3,6 +3,11 @@ package proxy
 import (
 	"context"
 	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/pkg/errors"
@@ -12,6 +17,12 @@ import (
 // error that occurs while processing routes.
 type ErrorHandler func(context.Context, events.APIGatewayV2HTTPRequest, error) (events.APIGatewayProxyResponse, error)
 
+// ErrorHandlerV2 defines the function interface the router uses to handle any
+// error that occurs while processing routes, with access to the route context.
+// When the error didn't come from a matched route, such as a not found error,
+// the context's MatchedRoute is nil and it has no Params.
+type ErrorHandlerV2 func(*RouteContext, error) (events.APIGatewayProxyResponse, error)
+
 // CatchAllHandler defines the function interface the router uses to handle any
 // request that doesn't match a route.
 type CatchAllHandler func(context.Context, events.APIGatewayV2HTTPRequest) (events.APIGatewayProxyResponse, error)
@@ -22,13 +33,29 @@ type CatchAllHandler func(context.Context, events.APIGatewayV2HTTPRequest) (even
 //
 // Route matching is a simple process that loops through all routes added in the
 // order they were configured and checks if a match is present. If so that route
-// gets executed, otherwise it moves onto the next route for comparison.
+// gets executed, otherwise it moves onto the next route for comparison. See
+// SortBySpecificity to instead match more specific routes first.
 //
 // If the CatchAll handler is set any request that doesn't match a route will be
 // handled by it.
 //
 // If the CatchError handler is set any route that returns an error will first
-// be passed into the hander for additional processing.
+// be passed into the hander for additional processing. CatchErrorV2 does the
+// same with access to the route context, and takes precedence over CatchError.
+//
+// If StrictParams is set a request with a param that is set to different values
+// by multiple sources, such as a regex capture and the query string, fails with
+// a 400 HTTPError instead of silently using the value with the highest
+// precedence. The error is passed to the CatchError handler if set.
+//
+// Middleware added via Use wraps the handler of every matched route. It does
+// not run for the CatchAll handler unless MiddlewareOnCatchAll is set. Route
+// middleware, provided when adding a route, only wraps that route's handler.
+// For a matched route the execution order is:
+//
+//	1) Router middleware, in the order added via Use
+//	2) Route middleware, in the order provided
+//	3) The route handler
 //
 //
 // Example:
@@ -60,11 +87,40 @@ type CatchAllHandler func(context.Context, events.APIGatewayV2HTTPRequest) (even
 //	}
 //
 type Router struct {
-	Routes     []*Route
-	CatchAll   CatchAllHandler
-	CatchError ErrorHandler
-
-	errors []error
+	Routes               []*Route
+	CatchAll             CatchAllHandler
+	CatchError           ErrorHandler
+	CatchErrorV2         ErrorHandlerV2
+	MiddlewareOnCatchAll bool
+	StrictParams         bool
+
+	errors     []error
+	middleware []Middleware
+	cors       *CORSOptions
+	mounts     []*mount
+
+	sortBySpecificity bool
+	trailingSlash     TrailingSlashPolicy
+	paramPrecedence   []ParamSource
+	formMethods       []HttpMethod
+
+	notFound         *events.APIGatewayProxyResponse
+	maxBodyBytes     int64
+	maxResponseBytes int64
+	rateLimiter      *rateLimiter
+	jsonErrors       bool
+	statusCode       int
+	stagePrefix      string
+	matcher          *matcher
+
+	defaultHeaders map[string]string
+
+	logger  func(LogEntry)
+	nowFunc func() time.Time
+	timeout time.Duration
+
+	emfNamespace string
+	emfWriter    io.Writer
 }
 
 // Valid returns true if the routers' routes have all been built successfully.
@@ -73,9 +129,186 @@ func (router *Router) Valid() bool {
 	return len(router.errors) == 0
 }
 
-// AddRoute appends route to the list of routes used for request matching.
+// RouteList returns the method and pattern of each route, in matching order.
+func (router *Router) RouteList() []string {
+	list := make([]string, 0, len(router.Routes))
+
+	for _, route := range router.Routes {
+		list = append(list, route.String())
+	}
+
+	return list
+}
+
+// Describe returns a multi-line description of the router's routes and whether
+// the CatchAll and CatchError handlers are set, e.g. for logging when a request
+// unexpectedly doesn't match.
+func (router *Router) Describe() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "routes: %d\n", len(router.Routes))
+
+	for _, route := range router.RouteList() {
+		fmt.Fprintf(&b, "  %s\n", route)
+	}
+
+	fmt.Fprintf(&b, "catch all: %t\n", router.CatchAll != nil)
+	fmt.Fprintf(&b, "catch error: %t\n", router.hasCatchError())
+
+	return b.String()
+}
+
+// AllowedMethods returns the distinct methods of the routes whose pattern
+// matches the path, ordered as the HttpMethod constants, e.g. for an Allow
+// header on a 405 or OPTIONS response.
+func (router *Router) AllowedMethods(path string) []HttpMethod {
+	seen := make(map[HttpMethod]bool)
+	methods := []HttpMethod{}
+
+	for _, route := range router.Routes {
+		if seen[route.Method] || !route.Regex.MatchString(path) {
+			continue
+		}
+
+		seen[route.Method] = true
+		methods = append(methods, route.Method)
+	}
+
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i] < methods[j]
+	})
+
+	return methods
+}
+
+// MatchRoute returns the route that would handle the request and the params
+// that would be extracted for it, without executing the handler, middleware or
+// any CatchAll handler. The request path is resolved as by Route, so the stage
+// prefix is stripped if set. False is returned if no route matches.
+//
+// If the params can't be extracted, e.g. because of a malformed form body, the
+// route is still returned with nil params.
+func (router *Router) MatchRoute(request events.APIGatewayV2HTTPRequest) (*Route, map[string]string, bool) {
+	request = router.resolvePath(request)
+
+	route, groups := router.match(request)
+	if route == nil {
+		return nil, nil, false
+	}
+
+	params, err := route.extractParams(request, groups)
+	if err != nil {
+		return route, nil, true
+	}
+
+	return route, params, true
+}
+
+// AddRoute appends route to the list of routes used for request matching. If
+// SortBySpecificity is enabled the routes are re-sorted.
+//
+// A build error is added if the route duplicates the method and pattern, or the
+// name, of an existing route, or if it can never match because an existing
+// route with the same method is a catch all, such as '.*'. Shadowing isn't
+// checked when SortBySpecificity is enabled, as catch all routes are then
+// matched last.
 func (router *Router) AddRoute(route *Route) {
+	router.checkRoute(route)
 	router.Routes = append(router.Routes, route)
+	router.matcher = nil
+
+	if router.sortBySpecificity {
+		router.sortRoutes()
+	}
+}
+
+// RemoveRoute removes the route with the given method and pattern and returns
+// whether it was found. The pattern is either as provided when adding the route
+// or its compiled regex.
+func (router *Router) RemoveRoute(method HttpMethod, pattern string) bool {
+	i := router.findRoute(method, pattern)
+	if i < 0 {
+		return false
+	}
+
+	router.Routes = append(router.Routes[:i], router.Routes[i+1:]...)
+	router.matcher = nil
+
+	return true
+}
+
+// ReplaceHandler replaces the handler of the route with the given method and
+// pattern and returns whether it was found, see RemoveRoute for the pattern.
+// The route's middleware and position are kept.
+func (router *Router) ReplaceHandler(method HttpMethod, pattern string, handler RouteHandler) bool {
+	i := router.findRoute(method, pattern)
+	if i < 0 {
+		return false
+	}
+
+	router.Routes[i].Handler = handler
+
+	return true
+}
+
+// findRoute returns the index of the route with the given method and pattern,
+// or -1 if there is none.
+func (router *Router) findRoute(method HttpMethod, pattern string) int {
+	patterns := []string{pattern}
+	if route, err := router.compileRoute(method, pattern, nil); err == nil {
+		patterns = append(patterns, route.Regex.String())
+	}
+
+	for i, route := range router.Routes {
+		if route.Method != method {
+			continue
+		}
+
+		for _, p := range patterns {
+			if route.Regex.String() == p {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// Use appends middleware that wraps the handler of every matched route. The
+// middleware runs in the order it was added.
+func (router *Router) Use(middleware ...Middleware) {
+	router.middleware = append(router.middleware, middleware...)
+}
+
+// EnableCORS configures the router to answer CORS preflight requests and to add
+// the CORS headers to all other responses.
+func (router *Router) EnableCORS(opts CORSOptions) {
+	router.cors = &opts
+}
+
+// checkRoute adds a build error if the route is a duplicate of, or shadowed by,
+// an existing route, or if its name is already in use.
+func (router *Router) checkRoute(route *Route) {
+	for _, existing := range router.Routes {
+		if route.Name != "" && existing.Name == route.Name {
+			router.AddBuildError(fmt.Errorf("duplicate route name '%s' for '%s'", route.Name, route))
+			return
+		}
+
+		if existing.Method != route.Method {
+			continue
+		}
+
+		if existing.Regex.String() == route.Regex.String() {
+			router.AddBuildError(fmt.Errorf("duplicate route '%s'", route))
+			return
+		}
+
+		if !router.sortBySpecificity && existing.isCatchAll() {
+			router.AddBuildError(fmt.Errorf("route '%s' is shadowed by catch all route '%s'", route, existing))
+			return
+		}
+	}
 }
 
 // AddBuildError appends an error to the list of router errors.
@@ -83,16 +316,34 @@ func (router *Router) AddBuildError(err error) {
 	router.errors = append(router.errors, err)
 }
 
-// BuildErrors returns a single error that encapsulates all the route errors
-// found during router construction.
-func (router *Router) BuildErrors() error {
-	topError := errors.New("failed building router")
+// BuildError aggregates the errors found during router construction. Its
+// Unwrap method exposes the individual errors, so errors.Is and errors.As can
+// inspect them, e.g. for the *syntax.Error of a regex that failed to compile.
+type BuildError struct {
+	Errors []error
+}
+
+// Error returns the build errors, most recent first, followed by 'failed
+// building router'.
+func (e *BuildError) Error() string {
+	messages := make([]string, 0, len(e.Errors)+1)
 
-	for _, err := range router.errors {
-		topError = errors.Wrap(topError, err.Error())
+	for i := len(e.Errors) - 1; i >= 0; i-- {
+		messages = append(messages, e.Errors[i].Error())
 	}
 
-	return topError
+	return strings.Join(append(messages, "failed building router"), ": ")
+}
+
+// Unwrap returns the individual build errors.
+func (e *BuildError) Unwrap() []error {
+	return e.Errors
+}
+
+// BuildErrors returns a single *BuildError that encapsulates all the route
+// errors found during router construction.
+func (router *Router) BuildErrors() error {
+	return &BuildError{Errors: append([]error(nil), router.errors...)}
 }
 
 // AddRouteIfNoError appends the provided route if no error is present.
@@ -108,49 +359,95 @@ func (router *Router) AddRouteIfNoError(route *Route, err error) {
 	}
 }
 
-// GET adds a new GET route with the specified pattern match and handler.
-func (router *Router) GET(match string, handler RouteHandler) {
-	router.AddRouteIfNoError(NewRoute(GET, match, handler))
+// GET adds a new GET route with the specified pattern match, handler and
+// optional route middleware.
+func (router *Router) GET(match string, handler RouteHandler, middleware ...Middleware) {
+	router.AddRouteIfNoError(router.compileRoute(GET, match, handler, middleware...))
+}
+
+// HEAD adds a new HEAD route with the specified pattern match, handler and
+// optional route middleware.
+func (router *Router) HEAD(match string, handler RouteHandler, middleware ...Middleware) {
+	router.AddRouteIfNoError(router.compileRoute(HEAD, match, handler, middleware...))
+}
+
+// POST adds a new POST route with the specified pattern match, handler and
+// optional route middleware.
+func (router *Router) POST(match string, handler RouteHandler, middleware ...Middleware) {
+	router.AddRouteIfNoError(router.compileRoute(POST, match, handler, middleware...))
+}
+
+// PUT adds a new PUT route with the specified pattern match, handler and
+// optional route middleware.
+func (router *Router) PUT(match string, handler RouteHandler, middleware ...Middleware) {
+	router.AddRouteIfNoError(router.compileRoute(PUT, match, handler, middleware...))
 }
 
-// HEAD adds a new HEAD route with the specified pattern match and handler.
-func (router *Router) HEAD(match string, handler RouteHandler) {
-	router.AddRouteIfNoError(NewRoute(HEAD, match, handler))
+// DELETE adds a new DELETE route with the specified pattern match, handler and
+// optional route middleware.
+func (router *Router) DELETE(match string, handler RouteHandler, middleware ...Middleware) {
+	router.AddRouteIfNoError(router.compileRoute(DELETE, match, handler, middleware...))
 }
 
-// POST adds a new POST route with the specified pattern match and handler.
-func (router *Router) POST(match string, handler RouteHandler) {
-	router.AddRouteIfNoError(NewRoute(POST, match, handler))
+// CONNECT adds a new CONNECT route with the specified pattern match, handler and
+// optional route middleware.
+func (router *Router) CONNECT(match string, handler RouteHandler, middleware ...Middleware) {
+	router.AddRouteIfNoError(router.compileRoute(CONNECT, match, handler, middleware...))
 }
 
-// PUT adds a new PUT route with the specified pattern match and handler.
-func (router *Router) PUT(match string, handler RouteHandler) {
-	router.AddRouteIfNoError(NewRoute(PUT, match, handler))
+// OPTIONS adds a new OPTIONS route with the specified pattern match, handler and
+// optional route middleware.
+func (router *Router) OPTIONS(match string, handler RouteHandler, middleware ...Middleware) {
+	router.AddRouteIfNoError(router.compileRoute(OPTIONS, match, handler, middleware...))
 }
 
-// DELETE adds a new DELETE route with the specified pattern match and handler.
-func (router *Router) DELETE(match string, handler RouteHandler) {
-	router.AddRouteIfNoError(NewRoute(DELETE, match, handler))
+// TRACE adds a new TRACE route with the specified pattern match, handler and
+// optional route middleware.
+func (router *Router) TRACE(match string, handler RouteHandler, middleware ...Middleware) {
+	router.AddRouteIfNoError(router.compileRoute(TRACE, match, handler, middleware...))
 }
 
-// CONNECT adds a new CONNECT route with the specified pattern match and handler.
-func (router *Router) CONNECT(match string, handler RouteHandler) {
-	router.AddRouteIfNoError(NewRoute(CONNECT, match, handler))
+// PATCH adds a new PATCH route with the specified pattern match, handler and
+// optional route middleware.
+func (router *Router) PATCH(match string, handler RouteHandler, middleware ...Middleware) {
+	router.AddRouteIfNoError(router.compileRoute(PATCH, match, handler, middleware...))
 }
 
-// OPTIONS adds a new OPTIONS route with the specified pattern match and handler.
-func (router *Router) OPTIONS(match string, handler RouteHandler) {
-	router.AddRouteIfNoError(NewRoute(OPTIONS, match, handler))
+// Handle adds a route for each of the methods with the same pattern match,
+// handler and optional route middleware. The pattern is compiled once, so an
+// invalid pattern adds a single build error rather than one per method.
+//
+// Example:
+//
+//	router.Handle([]HttpMethod{POST, PUT}, "/users/:id", saveUserHandler)
+func (router *Router) Handle(methods []HttpMethod, match string, handler RouteHandler, middleware ...Middleware) {
+	if len(methods) == 0 {
+		router.AddBuildError(fmt.Errorf("no methods given for pattern '%s'", match))
+		return
+	}
+
+	route, err := router.compileRoute(methods[0], match, handler, middleware...)
+	if err != nil {
+		router.AddBuildError(err)
+		return
+	}
+
+	for _, method := range methods {
+		r := *route
+		r.Method = method
+		router.AddRoute(&r)
+	}
 }
 
-// TRACE adds a new TRACE route with the specified pattern match and handler.
-func (router *Router) TRACE(match string, handler RouteHandler) {
-	router.AddRouteIfNoError(NewRoute(TRACE, match, handler))
+// Health adds a GET route at the literal path that responds with a 200 and the
+// json body {"status":"ok"}, for use as a health check or ping endpoint.
+func (router *Router) Health(path string) {
+	router.GET(regexp.QuoteMeta(path), healthHandler)
 }
 
-// PATCH adds a new PATCH route with the specified pattern match and handler.
-func (router *Router) PATCH(match string, handler RouteHandler) {
-	router.AddRouteIfNoError(NewRoute(PATCH, match, handler))
+// healthHandler responds to health checks.
+func healthHandler(*RouteContext) (events.APIGatewayProxyResponse, error) {
+	return JSON(200, map[string]string{"status": "ok"})
 }
 
 // AddCatchAllHandler attaches a catchall handler to the router.
@@ -158,6 +455,51 @@ func (router *Router) AddCatchAllHandler(handler CatchAllHandler) {
 	router.CatchAll = handler
 }
 
+// AddErrorHandlerV2 attaches an error handler with access to the route context
+// to the router. It takes precedence over any handler set via AddErrorHandler.
+func (router *Router) AddErrorHandlerV2(handler ErrorHandlerV2) {
+	router.CatchErrorV2 = handler
+}
+
+// hasCatchError returns true if either error handler is set.
+func (router *Router) hasCatchError() bool {
+	return router.CatchErrorV2 != nil || router.CatchError != nil
+}
+
+// catchError passes the error to CatchErrorV2 if set, otherwise to CatchError.
+func (router *Router) catchError(rctx *RouteContext, err error) (events.APIGatewayProxyResponse, error) {
+	if router.CatchErrorV2 != nil {
+		return router.CatchErrorV2(rctx, err)
+	}
+
+	return router.CatchError(rctx.Context, rctx.Request, err)
+}
+
+// SetNotFoundResponse sets the response returned when no route matches and no
+// CatchAll handler is set, instead of returning a not found error.
+func (router *Router) SetNotFoundResponse(status int, body string, headers map[string]string) {
+	router.notFound = &events.APIGatewayProxyResponse{
+		StatusCode: status,
+		Headers:    headers,
+		Body:       body,
+	}
+}
+
+// notFoundResponse returns a copy of the not found response so that it's
+// headers can't be modified by later processing.
+func (router *Router) notFoundResponse() events.APIGatewayProxyResponse {
+	response := *router.notFound
+
+	if response.Headers != nil {
+		response.Headers = make(map[string]string, len(router.notFound.Headers))
+		for k, v := range router.notFound.Headers {
+			response.Headers[k] = v
+		}
+	}
+
+	return response
+}
+
 // AddErrorHandler attaches a error handler to the router.
 func (router *Router) AddErrorHandler(handler ErrorHandler) {
 	router.CatchError = handler
@@ -166,27 +508,90 @@ func (router *Router) AddErrorHandler(handler ErrorHandler) {
 // routeInternal loops through all routes and checks if the request matches any
 // of them.
 //
+// If the request body exceeds the maximum body size a 413 response is returned
+// without matching.
+//
 // If there is a match it executes the route's handler.
 //
-// If the catch all handler is set and no route is matched it gets executed.
+// If no route is matched and the TrailingSlashRedirect policy is set, a request
+// whose path would match without its trailing slash is redirected.
 //
-// If there is no catch all handler and no route is matched an error is returned.
-func (router *Router) routeInternal(ctx context.Context, request events.APIGatewayV2HTTPRequest) (events.APIGatewayProxyResponse, error) {
-	for _, route := range router.Routes {
-		matched, groups := route.IsMatch(request)
+// If no route is matched the catch all handler of a mounted router whose prefix
+// matches gets executed, otherwise the catch all handler if set.
+//
+// If there is no catch all handler and no route is matched the not found
+// response is returned if set, otherwise a 404 HTTPError is returned.
+//
+// The context of the matched route is returned, or nil if no route matched. If
+// metrics isn't nil the matching and handler durations are recorded into it.
+func (router *Router) routeInternal(ctx context.Context, request events.APIGatewayV2HTTPRequest, metrics *RouteMetrics) (events.APIGatewayProxyResponse, *RouteContext, error) {
+	if request.RawPath == "" {
+		return events.APIGatewayProxyResponse{}, nil, errors.New("request has no RawPath or RequestContext.HTTP.Path, check the api gateway stage configuration")
+	}
 
-		if !matched {
-			continue
+	if router.bodyTooLarge(request) {
+		return bodyTooLargeResponse(), nil, nil
+	}
+
+	if response, ok := router.rateLimited(request); ok {
+		return response, nil, nil
+	}
+
+	mark := metrics.mark(router)
+	route, groups := router.match(request)
+	metrics.recordMatch(router, mark, route)
+
+	if route != nil {
+		if router.StrictParams {
+			if err := route.paramConflict(request, groups); err != nil {
+				return events.APIGatewayProxyResponse{}, nil, err
+			}
 		}
 
-		return route.Follow(ctx, request, groups)
+		mark = metrics.mark(router)
+		response, rctx, err := route.follow(ctx, request, groups, router.routeMiddleware())
+		metrics.recordHandler(router, mark)
+
+		return response, rctx, err
+	}
+
+	if response, ok := router.redirectTrailingSlash(request); ok {
+		return response, nil, nil
+	}
+
+	if response, ok, err := router.mountCatchAll(ctx, request); ok {
+		return response, nil, err
 	}
 
 	if router.CatchAll != nil {
+		response, err := router.catchAll(ctx, request)
+		return response, nil, err
+	}
+
+	if router.notFound != nil {
+		return router.notFoundResponse(), nil, nil
+	}
+
+	err := HTTPError{
+		Status:  404,
+		Message: fmt.Sprintf("'%s %s' not found", request.RequestContext.HTTP.Method, request.RawPath),
+	}
+
+	return events.APIGatewayProxyResponse{}, nil, err
+}
+
+// catchAll executes the catch all handler, wrapped in the router middleware
+// when MiddlewareOnCatchAll is set.
+func (router *Router) catchAll(ctx context.Context, request events.APIGatewayV2HTTPRequest) (events.APIGatewayProxyResponse, error) {
+	if !router.MiddlewareOnCatchAll {
 		return router.CatchAll(ctx, request)
 	}
 
-	return events.APIGatewayProxyResponse{}, fmt.Errorf("'%s %s' not found", request.RequestContext.HTTP.Method, request.RawPath)
+	handler := func(rctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return router.CatchAll(rctx.Context, rctx.Request)
+	}
+
+	return chain(handler, router.middleware...)(newRouteContext(ctx, request, map[string]string{}))
 }
 
 // Route loops through all routes and checks if the request matches any of them.
@@ -195,20 +600,111 @@ func (router *Router) routeInternal(ctx context.Context, request events.APIGatew
 //
 // If the catch all handler is set and no route is matched it gets executed.
 //
-// If there is no catch all handler and no route is matched an error is returned.
+// If there is no catch all handler and no route is matched the not found
+// response is returned if set, otherwise an error is returned.
 //
 // If there is an error handler set and an error occurs the errors the error
 // handler is executed and it's result returned.
+//
+// If CORS is enabled preflight requests are answered directly and the CORS
+// headers are added to all other responses.
+//
+// Some api gateway stage configurations leave RawPath empty, in which case the
+// RequestContext.HTTP.Path is matched instead. If both are empty an error is
+// returned rather than no route matching. If set the stage prefix is stripped
+// from the path, see SetStagePrefix.
+//
+// A response without a status code, which api gateway rejects, is given the
+// default status code if set, otherwise it is treated as an error, see
+// DefaultStatusCode.
+//
+// If JSON errors are enabled any remaining error is converted into a json
+// response, see EnableJSONErrors.
+//
+// The default response headers, if set, are added to every response, see
+// SetDefaultResponseHeaders.
+//
+// If a logger is set a LogEntry is emitted once the request has been handled,
+// likewise EMF metrics if enabled, see EnableEMFMetrics.
 func (router *Router) Route(ctx context.Context, request events.APIGatewayV2HTTPRequest) (events.APIGatewayProxyResponse, error) {
-	if router.CatchError == nil {
-		return router.routeInternal(ctx, request)
+	return router.route(ctx, request, nil)
+}
+
+// route routes the request as described by Route, recording the timing of
+// matching and handling into metrics if it isn't nil.
+func (router *Router) route(ctx context.Context, request events.APIGatewayV2HTTPRequest, metrics *RouteMetrics) (events.APIGatewayProxyResponse, error) {
+	start := router.now()
+	request = router.resolvePath(request)
+
+	if router.cors != nil && router.cors.isPreflight(request) {
+		response := router.applyDefaultHeaders(router.cors.preflight(request))
+		router.log(start, request, nil, response, nil)
+		router.emitMetrics(start, request, nil)
+		return response, nil
 	}
 
-	response, err := router.routeInternal(ctx, request)
+	response, rctx, err := router.routeInternal(ctx, request, metrics)
 
-	if err != nil {
-		return router.CatchError(ctx, request, err)
+	var route *Route
+	if rctx != nil {
+		route = rctx.MatchedRoute
+	}
+
+	if err == nil && response.StatusCode == 0 {
+		response, err = router.missingStatusCode(request, route, response)
+	}
+
+	routeErr := err
+
+	if err != nil && router.hasCatchError() {
+		if rctx == nil {
+			rctx = newRouteContext(ctx, request, map[string]string{})
+		}
+
+		response, err = router.catchError(rctx, err)
+	}
+
+	if err != nil && router.jsonErrors {
+		response, err = jsonError(err), nil
+	}
+
+	if router.cors != nil {
+		response = router.cors.apply(request, response)
+	}
+
+	response = router.applyDefaultHeaders(response)
+
+	router.log(start, request, route, response, routeErr)
+	router.emitMetrics(start, request, route)
+
+	return response, err
+}
+
+// SetStagePrefix sets the api gateway stage whose '/<stage>' prefix is stripped
+// from the request path before matching, for mappings that include the stage
+// in the path, e.g. '/prod/users/5' is matched as '/users/5'. By default the
+// path is matched as is.
+func (router *Router) SetStagePrefix(stage string) {
+	router.stagePrefix = "/" + strings.Trim(stage, "/")
+}
+
+// resolvePath returns the request with its RawPath set to the
+// RequestContext.HTTP.Path when it is empty and the stage prefix stripped.
+func (router *Router) resolvePath(request events.APIGatewayV2HTTPRequest) events.APIGatewayV2HTTPRequest {
+	if request.RawPath == "" {
+		request.RawPath = request.RequestContext.HTTP.Path
+	}
+
+	if router.stagePrefix == "" || router.stagePrefix == "/" {
+		return request
+	}
+
+	switch {
+	case request.RawPath == router.stagePrefix:
+		request.RawPath = "/"
+	case strings.HasPrefix(request.RawPath, router.stagePrefix+"/"):
+		request.RawPath = strings.TrimPrefix(request.RawPath, router.stagePrefix)
 	}
 
-	return response, nil
+	return request
 }
@@ -3,6 +3,8 @@ package proxy
 import (
 	"context"
 	"errors"
+	"regexp/syntax"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-lambda-go/events"
@@ -71,6 +73,33 @@ func TestRouter_BuildErrors(t *testing.T) {
 	assert.Equal(t, "some other error: some error: failed building router", err.Error())
 }
 
+func TestRouter_BuildErrors_unwrap(t *testing.T) {
+	sentinel := errors.New("sentinel")
+
+	sub := &Router{}
+	sub.AddBuildError(sentinel)
+
+	r := &Router{}
+	r.AddRouteIfNoError(NewRoute(GET, "asom (?P<unclosed.*)", testHandler))
+	r.Mount("/sub", sub)
+
+	err := r.BuildErrors()
+
+	var buildErr *BuildError
+	assert.True(t, errors.As(err, &buildErr))
+	assert.Len(t, buildErr.Errors, 2)
+
+	var syntaxErr *syntax.Error
+	assert.True(t, errors.As(err, &syntaxErr))
+	assert.Equal(t, syntax.ErrInvalidNamedCapture, syntaxErr.Code)
+
+	assert.True(t, errors.Is(err, sentinel))
+	assert.False(t, errors.Is(err, errors.New("sentinel")))
+
+	assert.Contains(t, err.Error(), "failed mounting router at '/sub': sentinel: failed compiling regex pattern 'asom (?P<unclosed.*)': ")
+	assert.True(t, strings.HasSuffix(err.Error(), ": failed building router"))
+}
+
 func TestRouter_AddRouteIfNoError(t *testing.T) {
 	r := &Router{}
 
@@ -347,3 +376,594 @@ func TestRouter_Route_CatchAll_noMatch(t *testing.T) {
 	assert.Equal(t, 404, response.StatusCode)
 	assert.Equal(t, "not found", response.Body)
 }
+
+func TestRouter_Use(t *testing.T) {
+	r := &Router{}
+	calls := []string{}
+
+	routeHandler := func(context *RouteContext) (events.APIGatewayProxyResponse, error) {
+		calls = append(calls, "handler")
+		return events.APIGatewayProxyResponse{StatusCode: 200}, nil
+	}
+
+	r.Use(appendMiddleware("first", &calls))
+	r.Use(appendMiddleware("second", &calls), appendMiddleware("third", &calls))
+	r.GET("/route", routeHandler)
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/route"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, []string{"first", "second", "third", "handler"}, calls)
+}
+
+func TestRouter_Use_shortCircuit(t *testing.T) {
+	r := &Router{}
+	called := false
+
+	routeHandler := func(context *RouteContext) (events.APIGatewayProxyResponse, error) {
+		called = true
+		return events.APIGatewayProxyResponse{StatusCode: 200}, nil
+	}
+
+	r.Use(func(next RouteHandler) RouteHandler {
+		return func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+			return events.APIGatewayProxyResponse{StatusCode: 401}, nil
+		}
+	})
+	r.GET("/route", routeHandler)
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/route"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 401, response.StatusCode)
+	assert.False(t, called)
+}
+
+func TestRouter_Use_catchAll(t *testing.T) {
+	calls := []string{}
+
+	catchAll := func(ctx context.Context, request events.APIGatewayV2HTTPRequest) (events.APIGatewayProxyResponse, error) {
+		calls = append(calls, "catchall")
+		return events.APIGatewayProxyResponse{StatusCode: 404}, nil
+	}
+
+	r := &Router{}
+	r.Use(appendMiddleware("mw", &calls))
+	r.AddCatchAllHandler(catchAll)
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/nope"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 404, response.StatusCode)
+	assert.Equal(t, []string{"catchall"}, calls)
+
+	calls = []string{}
+	r.MiddlewareOnCatchAll = true
+
+	response, err = r.Route(context.Background(), testRequest(GET, "/nope"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 404, response.StatusCode)
+	assert.Equal(t, []string{"mw", "catchall"}, calls)
+}
+
+func TestRouter_routeMiddleware(t *testing.T) {
+	r := &Router{}
+	calls := []string{}
+
+	auth := func(next RouteHandler) RouteHandler {
+		return func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+			calls = append(calls, "auth")
+			if ctx.Request.Headers["authorization"] != "let-me-in" {
+				return events.APIGatewayProxyResponse{StatusCode: 401}, nil
+			}
+			return next(ctx)
+		}
+	}
+
+	routeHandler := func(context *RouteContext) (events.APIGatewayProxyResponse, error) {
+		calls = append(calls, "handler")
+		return events.APIGatewayProxyResponse{StatusCode: 200}, nil
+	}
+
+	r.Use(appendMiddleware("router", &calls))
+	r.GET("/admin", routeHandler, auth, appendMiddleware("route", &calls))
+	r.GET("/public", routeHandler)
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/public"))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, []string{"router", "handler"}, calls)
+
+	calls = []string{}
+	response, err = r.Route(context.Background(), testRequest(GET, "/admin"))
+	assert.NoError(t, err)
+	assert.Equal(t, 401, response.StatusCode)
+	assert.Equal(t, []string{"router", "auth"}, calls)
+
+	calls = []string{}
+	request := testRequest(GET, "/admin")
+	request.Headers["authorization"] = "let-me-in"
+	response, err = r.Route(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, []string{"router", "auth", "route", "handler"}, calls)
+}
+
+func TestRouter_AddRoute_duplicate(t *testing.T) {
+	r := &Router{}
+	r.GET("/health", testHandler)
+	r.POST("/health", testHandler)
+	r.GET("/health", testHandler)
+
+	assert.False(t, r.Valid())
+	assert.Len(t, r.errors, 1)
+	assert.Equal(t, "duplicate route 'GET ^/health/?$'", r.errors[0].Error())
+}
+
+func TestRouter_AddRoute_shadowed(t *testing.T) {
+	r := &Router{}
+	r.OPTIONS(".*", testHandler)
+	r.GET("/yolo", testHandler)
+	r.OPTIONS("/yolo", testHandler)
+
+	assert.False(t, r.Valid())
+	assert.Len(t, r.errors, 1)
+	assert.Equal(t, "route 'OPTIONS ^/yolo/?$' is shadowed by catch all route 'OPTIONS ^.*/?$'", r.errors[0].Error())
+}
+
+func TestRouter_AddRoute_shadowed_sorted(t *testing.T) {
+	r := &Router{}
+	r.SortBySpecificity()
+	r.GET(".*", testHandler)
+	r.GET("/yolo", testHandler)
+
+	assert.True(t, r.Valid())
+}
+
+func TestRouter_Route_requestID(t *testing.T) {
+	r := &Router{}
+
+	r.GET("/route", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		id, _ := RequestIDFromContext(ctx.Context)
+		return events.APIGatewayProxyResponse{StatusCode: 200, Body: ctx.RequestID + " " + id}, nil
+	})
+
+	request := testRequest(GET, "/route")
+	request.RequestContext.RequestID = "abc"
+
+	response, err := r.Route(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "abc abc", response.Body)
+}
+
+func TestRouter_RouteList(t *testing.T) {
+	r := &Router{}
+
+	assert.Empty(t, r.RouteList())
+
+	r.GET("/yolo", testHandler)
+	r.POST("/users/(?P<id>[0-9]+)", testHandler)
+	r.DELETE("/yolo", testHandler)
+
+	expected := []string{
+		"GET ^/yolo/?$",
+		"POST ^/users/(?P<id>[0-9]+)/?$",
+		"DELETE ^/yolo/?$",
+	}
+
+	assert.Equal(t, expected, r.RouteList())
+}
+
+func TestRouter_Describe(t *testing.T) {
+	r := &Router{}
+	r.GET("/yolo", testHandler)
+	r.POST("/yolo", testHandler)
+	r.AddCatchAllHandler(statusCatchAll(404, "nope"))
+
+	expected := "routes: 2\n" +
+		"  GET ^/yolo/?$\n" +
+		"  POST ^/yolo/?$\n" +
+		"catch all: true\n" +
+		"catch error: false\n"
+
+	assert.Equal(t, expected, r.Describe())
+}
+
+func TestRouter_SetNotFoundResponse(t *testing.T) {
+	r := &Router{}
+	r.GET("/yolo", testHandler)
+	r.SetNotFoundResponse(404, `{"error": "not found"}`, map[string]string{"Content-Type": "application/json"})
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/nope"))
+	assert.NoError(t, err)
+	assert.Equal(t, 404, response.StatusCode)
+	assert.Equal(t, `{"error": "not found"}`, response.Body)
+	assert.Equal(t, map[string]string{"Content-Type": "application/json"}, response.Headers)
+
+	response, err = r.Route(context.Background(), testRequest(GET, "/yolo"))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+}
+
+func TestRouter_SetNotFoundResponse_copy(t *testing.T) {
+	r := &Router{}
+	r.SetNotFoundResponse(404, "not found", map[string]string{"Content-Type": "text/plain"})
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/nope"))
+	assert.NoError(t, err)
+	response.Headers["X-Extra"] = "yes"
+
+	response, err = r.Route(context.Background(), testRequest(GET, "/nope"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Content-Type": "text/plain"}, response.Headers)
+}
+
+func TestRouter_SetNotFoundResponse_catchAll(t *testing.T) {
+	r := &Router{}
+	r.SetNotFoundResponse(404, "not found", nil)
+	r.AddCatchAllHandler(statusCatchAll(418, "catch all"))
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/nope"))
+	assert.NoError(t, err)
+	assert.Equal(t, 418, response.StatusCode)
+	assert.Equal(t, "catch all", response.Body)
+}
+
+func TestRouter_Route_matchedRoute(t *testing.T) {
+	var pattern string
+
+	r := &Router{}
+	r.GET("/yolo", testHandler)
+	r.GET("/users/(?P<id>[0-9]+)", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		pattern = ctx.MatchedRoute.Regex.String()
+		return events.APIGatewayProxyResponse{StatusCode: 200}, nil
+	})
+
+	_, err := r.Route(context.Background(), testRequest(GET, "/users/12345"))
+	assert.NoError(t, err)
+	assert.Equal(t, "^/users/(?P<id>[0-9]+)/?$", pattern)
+}
+
+func TestRouter_Route_matchedRouteMiddleware(t *testing.T) {
+	var matched *Route
+
+	r := &Router{}
+	r.Use(func(next RouteHandler) RouteHandler {
+		return func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+			matched = ctx.MatchedRoute
+			return next(ctx)
+		}
+	})
+	r.GET("/users/(?P<id>[0-9]+)", testHandler)
+
+	_, err := r.Route(context.Background(), testRequest(GET, "/users/12345"))
+	assert.NoError(t, err)
+	assert.Equal(t, r.Routes[0], matched)
+}
+
+func TestRouter_AddErrorHandlerV2(t *testing.T) {
+	var rctx *RouteContext
+
+	r := &Router{}
+	r.GET("/users/(?P<id>[0-9]+)", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{}, errors.New("handler failed")
+	})
+	r.AddErrorHandlerV2(func(ctx *RouteContext, err error) (events.APIGatewayProxyResponse, error) {
+		rctx = ctx
+		return events.APIGatewayProxyResponse{StatusCode: 500, Body: err.Error()}, nil
+	})
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/users/42"))
+	assert.NoError(t, err)
+	assert.Equal(t, 500, response.StatusCode)
+	assert.Equal(t, "handler failed", response.Body)
+
+	assert.NotNil(t, rctx)
+	assert.Equal(t, r.Routes[0], rctx.MatchedRoute)
+	assert.Equal(t, map[string]string{"id": "42"}, rctx.Params)
+}
+
+func TestRouter_AddErrorHandlerV2_notFound(t *testing.T) {
+	var rctx *RouteContext
+
+	r := &Router{}
+	r.AddErrorHandlerV2(func(ctx *RouteContext, err error) (events.APIGatewayProxyResponse, error) {
+		rctx = ctx
+		return events.APIGatewayProxyResponse{StatusCode: 404, Body: err.Error()}, nil
+	})
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/nope"))
+	assert.NoError(t, err)
+	assert.Equal(t, 404, response.StatusCode)
+	assert.Equal(t, "'GET /nope' not found", response.Body)
+
+	assert.NotNil(t, rctx)
+	assert.Nil(t, rctx.MatchedRoute)
+	assert.Empty(t, rctx.Params)
+	assert.Equal(t, "/nope", rctx.Request.RawPath)
+}
+
+func TestRouter_AddErrorHandlerV2_precedence(t *testing.T) {
+	r := &Router{}
+	r.AddErrorHandler(func(ctx context.Context, request events.APIGatewayV2HTTPRequest, err error) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: 500, Body: "v1"}, nil
+	})
+	r.AddErrorHandlerV2(func(ctx *RouteContext, err error) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: 500, Body: "v2"}, nil
+	})
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/nope"))
+	assert.NoError(t, err)
+	assert.Equal(t, "v2", response.Body)
+}
+
+func TestRouter_AddErrorHandlerV2_mount(t *testing.T) {
+	var rctx *RouteContext
+
+	users := &Router{}
+	users.GET("/(?P<id>[0-9]+)", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{}, errors.New("handler failed")
+	})
+	users.AddErrorHandlerV2(func(ctx *RouteContext, err error) (events.APIGatewayProxyResponse, error) {
+		rctx = ctx
+		return events.APIGatewayProxyResponse{StatusCode: 503}, nil
+	})
+
+	r := &Router{}
+	r.Mount("/users", users)
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/users/42"))
+	assert.NoError(t, err)
+	assert.Equal(t, 503, response.StatusCode)
+	assert.Equal(t, "42", rctx.Params["id"])
+}
+
+func TestRouter_Health(t *testing.T) {
+	r := &Router{}
+	r.Health("/health")
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/health"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, `{"status":"ok"}`, response.Body)
+	assert.Equal(t, "application/json", response.Headers["Content-Type"])
+
+	_, err = r.Route(context.Background(), testRequest(GET, "/healthz"))
+	assert.Error(t, err)
+}
+
+func TestRouter_QueryRoute(t *testing.T) {
+	r := &Router{}
+	r.AddRouteIfNoError(NewQueryRoute(GET, `/search\?(.*&)?q=.*`, func(*RouteContext) (events.APIGatewayProxyResponse, error) {
+		return Text(200, "query")
+	}))
+	r.GET("/search", func(*RouteContext) (events.APIGatewayProxyResponse, error) {
+		return Text(200, "path")
+	})
+
+	request := testRequest(GET, "/search")
+	request.RawQueryString = "q=yolo"
+
+	response, err := r.Route(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, "query", response.Body)
+
+	request.RawQueryString = "page=2"
+
+	response, err = r.Route(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, "path", response.Body)
+}
+
+func TestRouter_AllowedMethods(t *testing.T) {
+	r := &Router{}
+	r.DELETE("/users/(?P<id>[0-9]+)", testHandler)
+	r.GET("/users", testHandler)
+	r.GET("/users/(?P<id>[0-9]+)", testHandler)
+	r.GET("/users/.*", testHandler)
+
+	assert.Equal(t, []HttpMethod{GET, DELETE}, r.AllowedMethods("/users/5"))
+	assert.Equal(t, []HttpMethod{GET}, r.AllowedMethods("/users"))
+	assert.Equal(t, []HttpMethod{}, r.AllowedMethods("/yolo"))
+}
+
+func TestRouter_Handle(t *testing.T) {
+	r := &Router{}
+	r.Handle([]HttpMethod{POST, PUT}, "/users/:id", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return Text(200, ctx.Request.RequestContext.HTTP.Method+" "+ctx.Params["id"])
+	})
+
+	assert.True(t, r.Valid())
+	assert.Equal(t, []string{"POST ^/users/(?P<id>[^/]+)/?$", "PUT ^/users/(?P<id>[^/]+)/?$"}, r.RouteList())
+
+	for _, method := range []HttpMethod{POST, PUT} {
+		response, err := r.Route(context.Background(), testRequest(method, "/users/5"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 200, response.StatusCode)
+		assert.Equal(t, method.String()+" 5", response.Body)
+	}
+
+	_, err := r.Route(context.Background(), testRequest(GET, "/users/5"))
+	assert.Error(t, err)
+}
+
+func TestRouter_Handle_errors(t *testing.T) {
+	r := &Router{}
+	r.Handle([]HttpMethod{POST, PUT, PATCH}, "/users/(?P<id", testHandler)
+
+	assert.Len(t, r.errors, 1)
+	assert.Empty(t, r.Routes)
+
+	r = &Router{}
+	r.Handle(nil, "/users", testHandler)
+
+	assert.Len(t, r.errors, 1)
+	assert.EqualError(t, r.errors[0], "no methods given for pattern '/users'")
+}
+
+func TestRouter_MatchRoute(t *testing.T) {
+	called := false
+	handler := func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		called = true
+		return Text(200, "called")
+	}
+
+	middlewareCalled := false
+
+	r := &Router{}
+	r.Use(func(next RouteHandler) RouteHandler {
+		middlewareCalled = true
+		return next
+	})
+	r.GET("/users", handler)
+	r.GET("/users/:id", handler)
+
+	request := testRequest(GET, "/users/5")
+	request.QueryStringParameters = map[string]string{"expand": "true"}
+
+	route, params, ok := r.MatchRoute(request)
+
+	assert.True(t, ok)
+	assert.Equal(t, "^/users/(?P<id>[^/]+)/?$", route.Regex.String())
+	assert.Equal(t, map[string]string{"id": "5", "expand": "true"}, params)
+	assert.False(t, called)
+	assert.False(t, middlewareCalled)
+}
+
+func TestRouter_MatchRoute_noMatch(t *testing.T) {
+	catchAll := false
+
+	r := &Router{}
+	r.GET("/users/:id", testHandler)
+	r.AddCatchAllHandler(func(ctx context.Context, request events.APIGatewayV2HTTPRequest) (events.APIGatewayProxyResponse, error) {
+		catchAll = true
+		return Text(200, "catch all")
+	})
+
+	route, params, ok := r.MatchRoute(testRequest(POST, "/users/5"))
+
+	assert.False(t, ok)
+	assert.Nil(t, route)
+	assert.Nil(t, params)
+	assert.False(t, catchAll)
+}
+
+func TestRouter_Route_httpPathFallback(t *testing.T) {
+	r := &Router{}
+	r.GET("/users/:id", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return Text(200, ctx.Params["id"])
+	})
+
+	request := testRequest(GET, "")
+	request.RequestContext.HTTP.Path = "/users/42"
+
+	response, err := r.Route(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "42", response.Body)
+}
+
+func TestRouter_Route_noPath(t *testing.T) {
+	r := &Router{}
+	r.CatchAll = func(context.Context, events.APIGatewayV2HTTPRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: 404}, nil
+	}
+
+	_, err := r.Route(context.Background(), testRequest(GET, ""))
+
+	assert.EqualError(t, err, "request has no RawPath or RequestContext.HTTP.Path, check the api gateway stage configuration")
+}
+
+func TestRouter_SetStagePrefix(t *testing.T) {
+	r := &Router{}
+	r.SetStagePrefix("prod")
+	r.GET("/", func(*RouteContext) (events.APIGatewayProxyResponse, error) {
+		return Text(200, "root")
+	})
+	r.GET("/users/:id", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return Text(200, ctx.Params["id"])
+	})
+
+	tests := []struct {
+		path string
+		body string
+	}{
+		{"/prod/users/5", "5"},
+		{"/users/5", "5"},
+		{"/prod", "root"},
+		{"/prod/", "root"},
+	}
+
+	for _, test := range tests {
+		response, err := r.Route(context.Background(), testRequest(GET, test.path))
+		assert.NoError(t, err, test.path)
+		assert.Equal(t, test.body, response.Body, test.path)
+	}
+
+	_, err := r.Route(context.Background(), testRequest(GET, "/production/users/5"))
+	assert.Error(t, err)
+}
+
+func TestRouter_SetStagePrefix_unset(t *testing.T) {
+	r := &Router{}
+	r.GET("/users/:id", testHandler)
+
+	_, err := r.Route(context.Background(), testRequest(GET, "/prod/users/5"))
+	assert.Error(t, err)
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/users/5"))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+}
+
+func TestRouter_ReplaceHandler(t *testing.T) {
+	r := &Router{}
+	r.GET("/users/:id", func(*RouteContext) (events.APIGatewayProxyResponse, error) {
+		return Text(200, "original")
+	})
+
+	replaced := r.ReplaceHandler(GET, "/users/:id", func(*RouteContext) (events.APIGatewayProxyResponse, error) {
+		return Text(200, "replaced")
+	})
+	assert.True(t, replaced)
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/users/5"))
+	assert.NoError(t, err)
+	assert.Equal(t, "replaced", response.Body)
+	assert.Len(t, r.Routes, 1)
+
+	assert.True(t, r.ReplaceHandler(GET, "^/users/(?P<id>[^/]+)/?$", testHandler))
+}
+
+func TestRouter_RemoveRoute(t *testing.T) {
+	r := &Router{}
+	r.GET("/users", testHandler)
+	r.GET("/users/:id", testHandler)
+	r.DELETE("/users/:id", testHandler)
+
+	assert.True(t, r.RemoveRoute(GET, "/users/:id"))
+	assert.Equal(t, []string{"GET ^/users/?$", "DELETE ^/users/(?P<id>[^/]+)/?$"}, r.RouteList())
+
+	_, err := r.Route(context.Background(), testRequest(GET, "/users/5"))
+	assert.Error(t, err)
+
+	response, err := r.Route(context.Background(), testRequest(DELETE, "/users/5"))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+}
+
+func TestRouter_RemoveRoute_noMatch(t *testing.T) {
+	r := &Router{}
+	r.GET("/users", testHandler)
+
+	assert.False(t, r.RemoveRoute(POST, "/users"))
+	assert.False(t, r.RemoveRoute(GET, "/yolo"))
+	assert.False(t, r.RemoveRoute(GET, "(invalid"))
+	assert.False(t, r.ReplaceHandler(GET, "/yolo", testHandler))
+	assert.Len(t, r.Routes, 1)
+}
@@ -0,0 +1,74 @@
+package proxy
+
+import (
+	"regexp/syntax"
+	"sort"
+)
+
+// specificity approximates how specific a route's pattern is by counting the
+// literal characters and the dynamic elements (capture groups, character
+// classes, repetitions, alternations, etc) at the top level of the pattern.
+func (route *Route) specificity() (literals int, dynamic int) {
+	re, err := syntax.Parse(route.Regex.String(), syntax.Perl)
+	if err != nil {
+		return 0, 0
+	}
+
+	var walk func(re *syntax.Regexp)
+	walk = func(re *syntax.Regexp) {
+		switch re.Op {
+		case syntax.OpLiteral:
+			literals += len(re.Rune)
+		case syntax.OpConcat:
+			for _, sub := range re.Sub {
+				walk(sub)
+			}
+		case syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText,
+			syntax.OpWordBoundary, syntax.OpNoWordBoundary, syntax.OpEmptyMatch:
+			// anchors and empty matches don't affect specificity
+		default:
+			dynamic++
+		}
+	}
+
+	walk(re)
+
+	return literals, dynamic
+}
+
+// moreSpecific returns true if route a is more specific than route b. Routes
+// with more literal characters are more specific, ties are broken by fewer
+// dynamic elements.
+func moreSpecific(a *Route, b *Route) bool {
+	al, ad := a.specificity()
+	bl, bd := b.specificity()
+
+	if al != bl {
+		return al > bl
+	}
+
+	return ad < bd
+}
+
+// SortBySpecificity orders the routes, now and as they are added, so that more
+// specific patterns are matched before less specific ones. Otherwise routes are
+// matched in the order they were added.
+//
+// Specificity is a heuristic: routes with more literal characters come first,
+// with ties broken by fewer dynamic elements such as capture groups, character
+// classes and repetitions. Routes of equal specificity keep their relative
+// order. For example '/users/new' is matched before '/users/(?P<id>[^/]+)'
+// which is matched before '.*', regardless of the order they were added.
+func (router *Router) SortBySpecificity() {
+	router.sortBySpecificity = true
+	router.sortRoutes()
+}
+
+// sortRoutes stable sorts the routes by specificity.
+func (router *Router) sortRoutes() {
+	router.matcher = nil
+
+	sort.SliceStable(router.Routes, func(i, j int) bool {
+		return moreSpecific(router.Routes[i], router.Routes[j])
+	})
+}
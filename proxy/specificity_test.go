@@ -0,0 +1,79 @@
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoute_specificity(t *testing.T) {
+	cases := []struct {
+		pattern          string
+		expectedLiterals int
+		expectedDynamic  int
+	}{
+		{"/users/new", 10, 1},
+		{"/users/(?P<id>[^/]+)", 7, 2},
+		{"/users/(?P<id>[^/]+)/edit", 12, 2},
+		{".*", 0, 2},
+	}
+
+	for _, c := range cases {
+		r, err := NewRoute(GET, c.pattern, testHandler)
+		assert.NoError(t, err)
+
+		literals, dynamic := r.specificity()
+		assert.Equal(t, c.expectedLiterals, literals, c.pattern)
+		assert.Equal(t, c.expectedDynamic, dynamic, c.pattern)
+	}
+}
+
+func TestRouter_SortBySpecificity(t *testing.T) {
+	r := &Router{}
+	r.GET(".*", bodyHandler("wild"))
+	r.GET("/users/(?P<id>[^/]+)", bodyHandler("user "))
+	r.SortBySpecificity()
+	r.GET("/users/new", bodyHandler("new"))
+
+	assert.Equal(t, "GET ^/users/new/?$", r.Routes[0].String())
+	assert.Equal(t, "GET ^/users/(?P<id>[^/]+)/?$", r.Routes[1].String())
+	assert.Equal(t, "GET ^.*/?$", r.Routes[2].String())
+
+	cases := []struct {
+		path     string
+		expected string
+	}{
+		{"/users/new", "new"},
+		{"/users/42", "user 42"},
+		{"/other", "wild"},
+	}
+
+	for _, c := range cases {
+		response, err := r.Route(context.Background(), testRequest(GET, c.path))
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, response.Body)
+	}
+}
+
+func TestRouter_SortBySpecificity_stable(t *testing.T) {
+	r := &Router{}
+	r.SortBySpecificity()
+	r.GET("/a/(?P<id>[^/]+)", testHandler)
+	r.POST("/a/(?P<id>[^/]+)", testHandler)
+	r.GET("/b/(?P<id>[^/]+)", testHandler)
+
+	assert.Equal(t, "GET ^/a/(?P<id>[^/]+)/?$", r.Routes[0].String())
+	assert.Equal(t, "POST ^/a/(?P<id>[^/]+)/?$", r.Routes[1].String())
+	assert.Equal(t, "GET ^/b/(?P<id>[^/]+)/?$", r.Routes[2].String())
+}
+
+func TestRouter_noSort(t *testing.T) {
+	r := &Router{}
+	r.GET("/users/(?P<id>[^/]+)", bodyHandler("user "))
+	r.GET("/users/new", bodyHandler("new"))
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/users/new"))
+	assert.NoError(t, err)
+	assert.Equal(t, "user new", response.Body)
+}
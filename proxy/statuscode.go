@@ -0,0 +1,33 @@
+package proxy
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// DefaultStatusCode sets the status code given to responses returned without
+// one, such as a zero value APIGatewayProxyResponse returned by a buggy
+// handler, which api gateway would otherwise reject with a 502.
+//
+// By default, or if code is zero, such a response is instead treated as an
+// error and passed to the CatchError handler, if set, as for any other error.
+func (router *Router) DefaultStatusCode(code int) {
+	router.statusCode = code
+}
+
+// missingStatusCode returns the response with the default status code set if
+// configured, otherwise an error identifying the route that returned it.
+func (router *Router) missingStatusCode(request events.APIGatewayV2HTTPRequest, route *Route, response events.APIGatewayProxyResponse) (events.APIGatewayProxyResponse, error) {
+	if router.statusCode != 0 {
+		response.StatusCode = router.statusCode
+		return response, nil
+	}
+
+	source := "catch all handler"
+	if route != nil {
+		source = fmt.Sprintf("route '%v'", route)
+	}
+
+	return response, fmt.Errorf("%s returned a response without a status code for '%s %s'", source, request.RequestContext.HTTP.Method, request.RawPath)
+}
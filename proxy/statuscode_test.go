@@ -0,0 +1,66 @@
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func zeroHandler(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+	return events.APIGatewayProxyResponse{}, nil
+}
+
+func TestRouter_DefaultStatusCode(t *testing.T) {
+	r := &Router{}
+	r.DefaultStatusCode(200)
+	r.GET("/zero", zeroHandler)
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/zero"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+}
+
+func TestRouter_DefaultStatusCode_unset(t *testing.T) {
+	r := &Router{}
+	r.GET("/zero", zeroHandler)
+
+	_, err := r.Route(context.Background(), testRequest(GET, "/zero"))
+
+	assert.EqualError(t, err, "route 'GET ^/zero/?$' returned a response without a status code for 'GET /zero'")
+}
+
+func TestRouter_DefaultStatusCode_catchError(t *testing.T) {
+	var caught error
+
+	r := &Router{}
+	r.GET("/zero", zeroHandler)
+	r.AddErrorHandler(func(ctx context.Context, request events.APIGatewayV2HTTPRequest, err error) (events.APIGatewayProxyResponse, error) {
+		caught = err
+		return Text(500, "oops")
+	})
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/zero"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 500, response.StatusCode)
+	assert.EqualError(t, caught, "route 'GET ^/zero/?$' returned a response without a status code for 'GET /zero'")
+}
+
+func TestRouter_DefaultStatusCode_catchAll(t *testing.T) {
+	r := &Router{}
+	r.CatchAll = func(ctx context.Context, request events.APIGatewayV2HTTPRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{}, nil
+	}
+
+	_, err := r.Route(context.Background(), testRequest(GET, "/any"))
+	assert.EqualError(t, err, "catch all handler returned a response without a status code for 'GET /any'")
+
+	r.DefaultStatusCode(204)
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/any"))
+	assert.NoError(t, err)
+	assert.Equal(t, 204, response.StatusCode)
+}
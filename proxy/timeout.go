@@ -0,0 +1,72 @@
+package proxy
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/pkg/errors"
+)
+
+// ErrHandlerTimeout is passed to the CatchError handler, along with a 504
+// response, when a route handler doesn't return before the handler timeout.
+var ErrHandlerTimeout = errors.New("handler timed out")
+
+// SetHandlerTimeout sets the duration a matched route has to handle a request.
+// The route's context.Context is cancelled once the timeout elapses, and if the
+// handler hasn't returned by then a 504 response is returned. If a CatchError
+// handler is set it is passed ErrHandlerTimeout along with the 504 response,
+// otherwise the timeout is logged and the 504 response is returned without an
+// error so that API Gateway passes it on rather than replying with a 502.
+//
+// The handler keeps running in the background after the timeout, so it should
+// watch its context to stop early. A duration of zero disables the timeout.
+func (router *Router) SetHandlerTimeout(d time.Duration) {
+	router.timeout = d
+}
+
+// timeoutMiddleware applies the handler timeout.
+func (router *Router) timeoutMiddleware(next RouteHandler) RouteHandler {
+	return func(rctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		ctx, cancel := context.WithTimeout(rctx.Context, router.timeout)
+		defer cancel()
+
+		tctx := *rctx
+		tctx.Context = ctx
+
+		type result struct {
+			response events.APIGatewayProxyResponse
+			err      error
+		}
+
+		done := make(chan result, 1)
+
+		go func() {
+			response, err := next(&tctx)
+			done <- result{response, err}
+		}()
+
+		select {
+		case r := <-done:
+			return r.response, r.err
+		case <-ctx.Done():
+			response := events.APIGatewayProxyResponse{StatusCode: 504}
+			if router.hasCatchError() {
+				return response, ErrHandlerTimeout
+			}
+
+			log.Printf("%s %s: %v after %v", rctx.Request.RequestContext.HTTP.Method, rctx.Request.RawPath, ErrHandlerTimeout, router.timeout)
+			return response, nil
+		}
+	}
+}
+
+// routeMiddleware returns the middleware that wraps matched routes.
+func (router *Router) routeMiddleware() []Middleware {
+	if router.timeout <= 0 {
+		return router.middleware
+	}
+
+	return append([]Middleware{router.timeoutMiddleware}, router.middleware...)
+}
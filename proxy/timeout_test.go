@@ -0,0 +1,83 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter_SetHandlerTimeout_slow(t *testing.T) {
+	r := &Router{}
+	r.SetHandlerTimeout(10 * time.Millisecond)
+
+	r.GET("/slow", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		select {
+		case <-ctx.Context.Done():
+		case <-time.After(time.Second):
+		}
+		return events.APIGatewayProxyResponse{StatusCode: 200}, nil
+	})
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/slow"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 504, response.StatusCode)
+}
+
+func TestRouter_SetHandlerTimeout_catchErrorV2(t *testing.T) {
+	r := &Router{}
+	r.SetHandlerTimeout(10 * time.Millisecond)
+	r.AddErrorHandlerV2(func(ctx *RouteContext, err error) (events.APIGatewayProxyResponse, error) {
+		assert.True(t, errors.Is(err, ErrHandlerTimeout))
+		return events.APIGatewayProxyResponse{StatusCode: 503}, nil
+	})
+
+	r.GET("/slow", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		<-ctx.Context.Done()
+		return events.APIGatewayProxyResponse{StatusCode: 200}, nil
+	})
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/slow"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 503, response.StatusCode)
+}
+
+func TestRouter_SetHandlerTimeout_catchError(t *testing.T) {
+	r := &Router{}
+	r.SetHandlerTimeout(10 * time.Millisecond)
+	r.AddErrorHandler(func(ctx context.Context, request events.APIGatewayV2HTTPRequest, err error) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: 504, Body: err.Error()}, nil
+	})
+
+	r.GET("/slow", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		<-ctx.Context.Done()
+		return events.APIGatewayProxyResponse{StatusCode: 200}, nil
+	})
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/slow"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 504, response.StatusCode)
+	assert.Equal(t, "handler timed out", response.Body)
+}
+
+func TestRouter_SetHandlerTimeout_fast(t *testing.T) {
+	r := &Router{}
+	r.SetHandlerTimeout(time.Second)
+
+	r.GET("/fast", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		_, ok := ctx.Context.Deadline()
+		assert.True(t, ok)
+		return events.APIGatewayProxyResponse{StatusCode: 200}, nil
+	})
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/fast"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+}
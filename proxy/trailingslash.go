@@ -0,0 +1,92 @@
+package proxy
+
+import (
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// TrailingSlashPolicy controls how routes added via the router treat a trailing
+// slash on the request path.
+type TrailingSlashPolicy int
+
+const (
+	// TrailingSlashLenient matches routes with or without a trailing slash, so
+	// '/yolo' and '/yolo/' are equivalent. This is the default.
+	TrailingSlashLenient TrailingSlashPolicy = iota
+
+	// TrailingSlashStrict matches routes exactly, so '/yolo/' doesn't match the
+	// route '/yolo'.
+	TrailingSlashStrict
+
+	// TrailingSlashRedirect matches routes exactly and redirects a request with
+	// a trailing slash to the path without it when that path matches a route.
+	// GET and HEAD requests receive a 301, all other methods a 308 so the method
+	// and body are preserved.
+	TrailingSlashRedirect
+)
+
+// SetTrailingSlash sets the trailing slash policy. It only applies to routes
+// added via the router or its groups after it is set, routes created directly
+// via NewRoute are always lenient.
+func (router *Router) SetTrailingSlash(policy TrailingSlashPolicy) {
+	router.trailingSlash = policy
+}
+
+// compileRoute returns a Route for the specified method, pattern and handler
+// following the router's trailing slash policy, param precedence and form
+// methods.
+func (router *Router) compileRoute(method HttpMethod, pattern string, handler RouteHandler, middleware ...Middleware) (*Route, error) {
+	route, err := newRoute(method, pattern, router.trailingSlash == TrailingSlashLenient, handler, middleware...)
+	if err != nil {
+		return nil, err
+	}
+
+	route.ParamPrecedence = router.paramPrecedence
+	route.FormMethods = router.formMethods
+
+	return route, nil
+}
+
+// redirectTrailingSlash returns a redirect response if the TrailingSlashRedirect
+// policy is set and the request path, without its trailing slash, matches a
+// route. The stage prefix, if set, is added back to the location so the
+// redirect stays within the stage, see SetStagePrefix.
+func (router *Router) redirectTrailingSlash(request events.APIGatewayV2HTTPRequest) (events.APIGatewayProxyResponse, bool) {
+	if router.trailingSlash != TrailingSlashRedirect {
+		return events.APIGatewayProxyResponse{}, false
+	}
+
+	path := strings.TrimRight(request.RawPath, "/")
+	if path == request.RawPath || path == "" {
+		return events.APIGatewayProxyResponse{}, false
+	}
+
+	redirect := request
+	redirect.RawPath = path
+
+	for _, route := range router.Routes {
+		if matched, _ := route.IsMatch(redirect); !matched {
+			continue
+		}
+
+		location := path
+		if router.stagePrefix != "" && router.stagePrefix != "/" {
+			location = router.stagePrefix + path
+		}
+
+		if request.RawQueryString != "" {
+			location += "?" + request.RawQueryString
+		}
+
+		status := 308
+		if request.RequestContext.HTTP.Method == GET.String() || request.RequestContext.HTTP.Method == HEAD.String() {
+			status = 301
+		}
+
+		response, _ := Redirect(status, location)
+		return response, true
+	}
+
+	return events.APIGatewayProxyResponse{}, false
+}
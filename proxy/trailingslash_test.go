@@ -0,0 +1,119 @@
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter_SetTrailingSlash_lenient(t *testing.T) {
+	r := &Router{}
+	r.GET("/yolo", testHandler)
+
+	for _, path := range []string{"/yolo", "/yolo/"} {
+		response, err := r.Route(context.Background(), testRequest(GET, path))
+		assert.NoError(t, err, path)
+		assert.Equal(t, 200, response.StatusCode, path)
+	}
+}
+
+func TestRouter_SetTrailingSlash_strict(t *testing.T) {
+	r := &Router{}
+	r.SetTrailingSlash(TrailingSlashStrict)
+	r.GET("/yolo", testHandler)
+	r.GET("/dir/", testHandler)
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/yolo"))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+
+	_, err = r.Route(context.Background(), testRequest(GET, "/yolo/"))
+	assert.EqualError(t, err, "'GET /yolo/' not found")
+
+	response, err = r.Route(context.Background(), testRequest(GET, "/dir/"))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+
+	_, err = r.Route(context.Background(), testRequest(GET, "/dir"))
+	assert.EqualError(t, err, "'GET /dir' not found")
+}
+
+func TestRouter_SetTrailingSlash_redirect(t *testing.T) {
+	r := &Router{}
+	r.SetTrailingSlash(TrailingSlashRedirect)
+	r.GET("/yolo", testHandler)
+	r.POST("/yolo", testHandler)
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/yolo"))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+
+	response, err = r.Route(context.Background(), testRequest(GET, "/yolo/"))
+	assert.NoError(t, err)
+	assert.Equal(t, 301, response.StatusCode)
+	assert.Equal(t, "/yolo", response.Headers["Location"])
+
+	request := testRequest(GET, "/yolo/")
+	request.RawQueryString = "a=1&b=2"
+	response, err = r.Route(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, 301, response.StatusCode)
+	assert.Equal(t, "/yolo?a=1&b=2", response.Headers["Location"])
+
+	response, err = r.Route(context.Background(), testRequest(POST, "/yolo/"))
+	assert.NoError(t, err)
+	assert.Equal(t, 308, response.StatusCode)
+	assert.Equal(t, "/yolo", response.Headers["Location"])
+}
+
+func TestRouter_SetTrailingSlash_redirectStagePrefix(t *testing.T) {
+	r := &Router{}
+	r.SetStagePrefix("prod")
+	r.SetTrailingSlash(TrailingSlashRedirect)
+	r.GET("/users", testHandler)
+
+	request := testRequest(GET, "/prod/users/")
+	request.RawQueryString = "page=2"
+
+	response, err := r.Route(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, 301, response.StatusCode)
+	assert.Equal(t, "/prod/users?page=2", response.Headers["Location"])
+
+	response, err = r.Route(context.Background(), testRequest(GET, "/prod/users"))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+}
+
+func TestRouter_SetTrailingSlash_redirectNoMatch(t *testing.T) {
+	r := &Router{}
+	r.SetTrailingSlash(TrailingSlashRedirect)
+	r.GET("/yolo", testHandler)
+	r.CatchAll = statusCatchAll(404, "nope")
+
+	response, err := r.Route(context.Background(), testRequest(PUT, "/yolo/"))
+	assert.NoError(t, err)
+	assert.Equal(t, 404, response.StatusCode)
+
+	response, err = r.Route(context.Background(), testRequest(GET, "/other/"))
+	assert.NoError(t, err)
+	assert.Equal(t, 404, response.StatusCode)
+
+	response, err = r.Route(context.Background(), testRequest(GET, "/"))
+	assert.NoError(t, err)
+	assert.Equal(t, 404, response.StatusCode)
+}
+
+func TestRouter_SetTrailingSlash_group(t *testing.T) {
+	r := &Router{}
+	r.SetTrailingSlash(TrailingSlashStrict)
+	r.Group("/api").GET("/yolo", testHandler)
+
+	_, err := r.Route(context.Background(), testRequest(GET, "/api/yolo/"))
+	assert.Error(t, err)
+
+	response, err := r.Route(context.Background(), testRequest(GET, "/api/yolo"))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+}
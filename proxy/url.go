@@ -0,0 +1,116 @@
+package proxy
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// NamedRoute adds a new route with the given name, method, pattern match,
+// handler and optional route middleware. The name can be used with URL to
+// generate a path for the route.
+func (router *Router) NamedRoute(name string, method HttpMethod, match string, handler RouteHandler, middleware ...Middleware) {
+	route, err := router.compileRoute(method, match, handler, middleware...)
+	if err != nil {
+		router.AddBuildError(err)
+		return
+	}
+
+	route.Name = name
+	router.AddRoute(route)
+}
+
+// GETNamed adds a new named GET route with the specified pattern match, handler
+// and optional route middleware.
+func (router *Router) GETNamed(name string, match string, handler RouteHandler, middleware ...Middleware) {
+	router.NamedRoute(name, GET, match, handler, middleware...)
+}
+
+// URL generates the path of the named route by substituting the params into the
+// route's named capture groups. An error is returned if the name is unknown, a
+// param is missing or doesn't match its capture group, or the pattern contains
+// dynamic parts other than named capture groups.
+//
+// Example:
+//
+//	router.GETNamed("user", "/users/(?P<id>[^/]+)", userHandler)
+//	path, err := router.URL("user", map[string]string{"id": "42"}) // "/users/42"
+func (router *Router) URL(name string, params map[string]string) (string, error) {
+	for _, route := range router.Routes {
+		if route.Name == name {
+			return route.url(params)
+		}
+	}
+
+	return "", fmt.Errorf("unknown route name '%s'", name)
+}
+
+// url generates the path of the route by substituting the params into the named
+// capture groups.
+func (route *Route) url(params map[string]string) (string, error) {
+	re, err := syntax.Parse(route.Regex.String(), syntax.Perl)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed parsing route '%s'", route)
+	}
+
+	subs := []*syntax.Regexp{re}
+	if re.Op == syntax.OpConcat {
+		subs = re.Sub
+	}
+
+	var path strings.Builder
+
+	for i, sub := range subs {
+		switch {
+		case sub.Op == syntax.OpBeginText || sub.Op == syntax.OpEndText || sub.Op == syntax.OpEmptyMatch:
+			continue
+		case sub.Op == syntax.OpLiteral:
+			path.WriteString(string(sub.Rune))
+		case sub.Op == syntax.OpQuest && i == len(subs)-2 && sub.Sub[0].Op == syntax.OpLiteral && string(sub.Sub[0].Rune) == "/":
+			// the optional trailing slash added by NewRoute
+			continue
+		case sub.Op == syntax.OpCapture && sub.Name != "":
+			value, ok := params[sub.Name]
+			if !ok {
+				return "", fmt.Errorf("missing param '%s' for route '%s'", sub.Name, route)
+			}
+
+			rx, err := regexp.Compile("^(?:" + sub.Sub[0].String() + ")$")
+			if err != nil || !rx.MatchString(value) {
+				return "", fmt.Errorf("param '%s' value '%s' doesn't match route '%s'", sub.Name, value, route)
+			}
+
+			if wildcard(sub.Sub[0]) {
+				path.WriteString(pathEscapeSegments(value))
+			} else {
+				path.WriteString(url.PathEscape(value))
+			}
+		default:
+			return "", fmt.Errorf("unable to generate url for '%s' from pattern '%s'", route, sub)
+		}
+	}
+
+	return path.String(), nil
+}
+
+// wildcard returns true if the capture group matches any remaining path, such
+// as the '.*' a '*name' placeholder expands to.
+func wildcard(re *syntax.Regexp) bool {
+	return (re.Op == syntax.OpStar || re.Op == syntax.OpPlus) &&
+		(re.Sub[0].Op == syntax.OpAnyCharNotNL || re.Sub[0].Op == syntax.OpAnyChar)
+}
+
+// pathEscapeSegments escapes each '/' separated segment of the value so that
+// the slashes are kept.
+func pathEscapeSegments(value string) string {
+	segments := strings.Split(value, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,116 @@
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter_URL(t *testing.T) {
+	r := &Router{}
+	r.GETNamed("users", "/users", testHandler)
+	r.GETNamed("user", "/users/(?P<id>[^/]+)", testHandler)
+	r.NamedRoute("order", POST, "/users/(?P<user>[0-9]+)/orders/(?P<order>[a-z0-9-]+)", testHandler)
+
+	assert.True(t, r.Valid())
+
+	path, err := r.URL("users", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "/users", path)
+
+	path, err = r.URL("user", map[string]string{"id": "42"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/users/42", path)
+
+	path, err = r.URL("user", map[string]string{"id": "a b"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/users/a%20b", path)
+
+	path, err = r.URL("order", map[string]string{"user": "42", "order": "abc-123"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/users/42/orders/abc-123", path)
+}
+
+func TestRouter_URL_roundTrip(t *testing.T) {
+	r := &Router{}
+	r.GETNamed("order", "/users/(?P<user>[0-9]+)/orders/(?P<order>[a-z0-9-]+)", testHandler)
+
+	path, err := r.URL("order", map[string]string{"user": "42", "order": "abc-123"})
+	assert.NoError(t, err)
+
+	matched, _ := r.Routes[0].IsMatch(testRequest(GET, path))
+	assert.True(t, matched)
+}
+
+func TestRouter_URL_wildcard(t *testing.T) {
+	r := &Router{}
+	r.GETNamed("files", "/files/*path", func(ctx *RouteContext) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: 200, Body: ctx.Params["path"]}, nil
+	})
+
+	path, err := r.URL("files", map[string]string{"path": "a/b c/d.txt"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/files/a/b%20c/d.txt", path)
+
+	path, err = r.URL("files", map[string]string{"path": "docs/2024/b.txt"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/files/docs/2024/b.txt", path)
+
+	response, err := r.Route(context.Background(), testRequest(GET, path))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, "docs/2024/b.txt", response.Body)
+}
+
+func TestRouter_URL_errors(t *testing.T) {
+	r := &Router{}
+	r.GETNamed("user", "/users/(?P<id>[0-9]+)", testHandler)
+	r.GETNamed("wild", "/files/.*", testHandler)
+	r.GETNamed("unnamed", "/things/([0-9]+)", testHandler)
+
+	_, err := r.URL("nope", nil)
+	assert.EqualError(t, err, "unknown route name 'nope'")
+
+	_, err = r.URL("user", map[string]string{})
+	assert.EqualError(t, err, "missing param 'id' for route 'GET ^/users/(?P<id>[0-9]+)/?$'")
+
+	_, err = r.URL("user", map[string]string{"id": "abc"})
+	assert.EqualError(t, err, "param 'id' value 'abc' doesn't match route 'GET ^/users/(?P<id>[0-9]+)/?$'")
+
+	_, err = r.URL("wild", nil)
+	assert.Error(t, err)
+
+	_, err = r.URL("unnamed", nil)
+	assert.Error(t, err)
+}
+
+func TestRouter_NamedRoute_duplicate(t *testing.T) {
+	r := &Router{}
+	r.GETNamed("user", "/users/(?P<id>[0-9]+)", testHandler)
+	r.NamedRoute("user", POST, "/users", testHandler)
+
+	assert.False(t, r.Valid())
+	assert.Contains(t, r.BuildErrors().Error(), "duplicate route name 'user'")
+}
+
+func TestRouter_NamedRoute_error(t *testing.T) {
+	r := &Router{}
+	r.GETNamed("user", "/users/(?P<id>[0-9]+", testHandler)
+
+	assert.False(t, r.Valid())
+	assert.Empty(t, r.Routes)
+}
+
+func TestRouter_URL_mount(t *testing.T) {
+	users := &Router{}
+	users.GETNamed("user", "/(?P<id>[0-9]+)", testHandler)
+
+	r := &Router{}
+	r.Mount("/users", users)
+
+	path, err := r.URL("user", map[string]string{"id": "42"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/users/42", path)
+}
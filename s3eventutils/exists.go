@@ -0,0 +1,61 @@
+package s3eventutils
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/pkg/errors"
+)
+
+// ObjectExists returns true if the record's object still exists, as it may
+// have been deleted between the event and its processing. A HeadObject request
+// is issued for the url decoded key, so the s3 client needs s3:GetObject
+// permission on the object. The current version of the object is checked.
+//
+// A not found response results in false and a nil error, any other failure is
+// returned as an error.
+func ObjectExists(ctx context.Context, s3api s3iface.S3API, record *events.S3EventRecord) (bool, error) {
+	key, err := decodeKey(record.S3.Object.Key)
+	if err != nil {
+		return false, err
+	}
+
+	input := &s3.HeadObjectInput{
+		Bucket: aws.String(record.S3.Bucket.Name),
+		Key:    aws.String(key),
+	}
+
+	_, err = s3api.HeadObjectWithContext(ctx, input)
+	if err == nil {
+		return true, nil
+	}
+
+	if notFound(err) {
+		return false, nil
+	}
+
+	return false, errors.Wrapf(err, "failed checking s3 object %s exists", uri(record.S3.Bucket.Name, key))
+}
+
+// notFound returns true if the s3 error is a 404 or NoSuchKey. A HeadObject
+// response has no body, so a missing key is reported as a 404 with the code
+// NotFound rather than NoSuchKey.
+func notFound(err error) bool {
+	if rerr, ok := err.(awserr.RequestFailure); ok && rerr.StatusCode() == http.StatusNotFound {
+		return true
+	}
+
+	if aerr, ok := err.(awserr.Error); ok {
+		switch aerr.Code() {
+		case s3.ErrCodeNoSuchKey, "NotFound":
+			return true
+		}
+	}
+
+	return false
+}
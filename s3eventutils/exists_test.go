@@ -0,0 +1,81 @@
+package s3eventutils
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/stretchr/testify/assert"
+)
+
+type headObjectMockS3Client struct {
+	s3iface.S3API
+	err   error
+	input *s3.HeadObjectInput
+}
+
+func (m *headObjectMockS3Client) HeadObjectWithContext(ctx aws.Context, input *s3.HeadObjectInput, opts ...request.Option) (*s3.HeadObjectOutput, error) {
+	m.input = input
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &s3.HeadObjectOutput{}, nil
+}
+
+func createS3EventRecord(bucket string, key string) *events.S3EventRecord {
+	record := &events.S3EventRecord{}
+	record.S3.Bucket.Name = bucket
+	record.S3.Object.Key = key
+
+	return record
+}
+
+func TestObjectExists(t *testing.T) {
+	client := &headObjectMockS3Client{}
+
+	exists, err := ObjectExists(context.Background(), client, createS3EventRecord("bktname", "some/file+name%281%29.txt"))
+
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, "bktname", *client.input.Bucket)
+	assert.Equal(t, "some/file name(1).txt", *client.input.Key)
+}
+
+func TestObjectExists_absent(t *testing.T) {
+	errs := []error{
+		awserr.NewRequestFailure(awserr.New("NotFound", "Not Found", nil), 404, "req1"),
+		awserr.New(s3.ErrCodeNoSuchKey, "The specified key does not exist.", nil),
+	}
+
+	for _, e := range errs {
+		exists, err := ObjectExists(context.Background(), &headObjectMockS3Client{err: e}, createS3EventRecord("bktname", "gone.txt"))
+
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	}
+}
+
+func TestObjectExists_error(t *testing.T) {
+	errs := []error{
+		awserr.NewRequestFailure(awserr.New("Forbidden", "Forbidden", nil), 403, "req1"),
+		errors.New("connection reset by peer"),
+	}
+
+	for _, e := range errs {
+		exists, err := ObjectExists(context.Background(), &headObjectMockS3Client{err: e}, createS3EventRecord("bktname", "file.txt"))
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed checking s3 object s3://bktname/file.txt exists")
+		assert.False(t, exists)
+	}
+
+	_, err := ObjectExists(context.Background(), &headObjectMockS3Client{}, createS3EventRecord("bktname", "bad%zz"))
+	assert.EqualError(t, err, "failed decoding s3 key 'bad%zz': invalid URL escape \"%zz\"")
+}
@@ -0,0 +1,27 @@
+package s3eventutils
+
+import (
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// FilterS3RecordsByEventName returns the records whose EventName starts with
+// one of the given prefixes.
+//
+// Matching is prefix based, so "ObjectCreated" matches both
+// "ObjectCreated:Put" and "ObjectCreated:CompleteMultipartUpload".
+func FilterS3RecordsByEventName(records []*events.S3EventRecord, prefixes ...string) []*events.S3EventRecord {
+	filtered := make([]*events.S3EventRecord, 0, len(records))
+
+	for _, record := range records {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(record.EventName, prefix) {
+				filtered = append(filtered, record)
+				break
+			}
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,50 @@
+package s3eventutils
+
+import (
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func createS3EventRecords(names ...string) []*events.S3EventRecord {
+	records := make([]*events.S3EventRecord, 0, len(names))
+	for _, name := range names {
+		records = append(records, &events.S3EventRecord{EventName: name})
+	}
+
+	return records
+}
+
+func TestFilterS3RecordsByEventName(t *testing.T) {
+	records := createS3EventRecords(
+		"ObjectCreated:Put",
+		"ObjectRemoved:Delete",
+		"ObjectCreated:CompleteMultipartUpload",
+		"ObjectRemoved:DeleteMarkerCreated",
+	)
+
+	cases := []struct {
+		prefixes []string
+		expected []*events.S3EventRecord
+	}{
+		{[]string{"ObjectCreated"}, []*events.S3EventRecord{records[0], records[2]}},
+		{[]string{"ObjectRemoved"}, []*events.S3EventRecord{records[1], records[3]}},
+		{[]string{"ObjectCreated:Put"}, []*events.S3EventRecord{records[0]}},
+		{[]string{"ObjectCreated", "ObjectRemoved:Delete"}, []*events.S3EventRecord{records[0], records[1], records[2], records[3]}},
+		{[]string{"ObjectRestore"}, []*events.S3EventRecord{}},
+		{nil, []*events.S3EventRecord{}},
+	}
+
+	for _, c := range cases {
+		actual := FilterS3RecordsByEventName(records, c.prefixes...)
+		assert.Equal(t, c.expected, actual)
+	}
+}
+
+func TestFilterS3RecordsByEventName_removedDropped(t *testing.T) {
+	records := createS3EventRecords("ObjectRemoved:Delete", "ObjectRemoved:DeleteMarkerCreated")
+
+	actual := FilterS3RecordsByEventName(records, "ObjectCreated")
+	assert.Empty(t, actual)
+}
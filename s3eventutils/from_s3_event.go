@@ -0,0 +1,87 @@
+package s3eventutils
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// S3Object identifies an object by its bucket and key.
+type S3Object struct {
+	Bucket string
+	Key    string
+}
+
+// S3ObjectDetails describes an object by its bucket, key, size and etag.
+type S3ObjectDetails struct {
+	S3Object
+	Size int64
+	ETag string
+}
+
+// S3ObjectsFromS3Event extracts the bucket and key of every record in the s3
+// event. The keys are url decoded.
+func S3ObjectsFromS3Event(s3Event events.S3Event) ([]S3Object, error) {
+	objects := make([]S3Object, 0, len(s3Event.Records))
+
+	for _, record := range s3Event.Records {
+		key, err := decodeKey(record.S3.Object.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, S3Object{Bucket: record.S3.Bucket.Name, Key: key})
+	}
+
+	return objects, nil
+}
+
+// UriFromS3Event extracts the s3 uri of every record in the s3 event. Folder
+// keys keep their trailing slash.
+func UriFromS3Event(s3Event events.S3Event) ([]string, error) {
+	objects, err := S3ObjectsFromS3Event(s3Event)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed getting s3 bucket and key")
+	}
+
+	uris := make([]string, 0, len(objects))
+
+	for _, object := range objects {
+		uris = append(uris, uri(object.Bucket, object.Key))
+	}
+
+	return uris, nil
+}
+
+// uri returns the s3 uri for the bucket and key.
+func uri(bucket string, key string) string {
+	u := fmt.Sprintf("s3://%s", path.Join(bucket, key))
+
+	if strings.HasSuffix(key, "/") {
+		u = u + "/"
+	}
+
+	return u
+}
+
+// cleanETag strips the surrounding quotes s3 may send with an etag. The
+// multipart suffix ('-N') is preserved.
+func cleanETag(etag string) string {
+	return strings.Trim(etag, `"`)
+}
+
+// decodeKey url decodes an s3 object key as sent in s3 event notifications.
+// Spaces arrive as '+' and a literal '+' arrives as '%2B'.
+func decodeKey(key string) (string, error) {
+	decoded, err := url.QueryUnescape(key)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed decoding s3 key '%s'", key)
+	}
+
+	return decoded, nil
+}
@@ -0,0 +1,72 @@
+package s3eventutils
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func readS3Event(t *testing.T, file string) events.S3Event {
+	b, err := os.ReadFile(file)
+	assert.NoError(t, err)
+
+	s3Event := events.S3Event{}
+	assert.NoError(t, json.Unmarshal(b, &s3Event))
+
+	return s3Event
+}
+
+func createS3Event(bucket string, key string) events.S3Event {
+	record := events.S3EventRecord{
+		S3: events.S3Entity{
+			Bucket: events.S3Bucket{Name: bucket},
+			Object: events.S3Object{Key: key},
+		},
+	}
+
+	return events.S3Event{Records: []events.S3EventRecord{record}}
+}
+
+func TestS3ObjectsFromS3Event(t *testing.T) {
+	s3Event := readS3Event(t, "testdata/valid_message_s3_multi.json")
+
+	objects, err := S3ObjectsFromS3Event(s3Event)
+	assert.NoError(t, err)
+
+	expected := []S3Object{
+		{Bucket: "bktname", Key: "some/file/in/s3.txt"},
+		{Bucket: "bktname2", Key: "some/file/in/my folder/"},
+	}
+	assert.Equal(t, expected, objects)
+}
+
+func TestS3ObjectsFromS3Event_empty(t *testing.T) {
+	objects, err := S3ObjectsFromS3Event(events.S3Event{})
+	assert.NoError(t, err)
+	assert.Empty(t, objects)
+}
+
+func TestS3ObjectsFromS3Event_error_key(t *testing.T) {
+	s3Event := createS3Event("bktname", "bad%ZZkey.txt")
+
+	_, err := S3ObjectsFromS3Event(s3Event)
+	assert.Error(t, err)
+}
+
+func TestUriFromS3Event(t *testing.T) {
+	s3Event := readS3Event(t, "testdata/valid_message_s3_multi.json")
+
+	uris, err := UriFromS3Event(s3Event)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"s3://bktname/some/file/in/s3.txt", "s3://bktname2/some/file/in/my folder/"}, uris)
+}
+
+func TestUriFromS3Event_error_key(t *testing.T) {
+	s3Event := createS3Event("bktname", "bad%ZZkey.txt")
+
+	_, err := UriFromS3Event(s3Event)
+	assert.Error(t, err)
+}
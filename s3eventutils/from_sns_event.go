@@ -3,59 +3,240 @@ package s3eventutils
 import (
 	"encoding/json"
 	"fmt"
-	"path"
-	"strings"
+	"net/url"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/aws/aws-lambda-go/events"
 )
 
-// S3EventRecordFromSNSWrapper extracts the underlying s3 event record wrapped
-// within the sns event.
-func S3EventRecordFromSNSWrapper(snsEvent events.SNSEvent) (*events.S3EventRecord, error) {
+// maxSNSUnwrapDepth is the number of nested sns notifications unwrapped before
+// giving up.
+const maxSNSUnwrapDepth = 3
+
+// S3EventRecordsFromSNSWrapper extracts all the underlying s3 event records
+// wrapped within the sns event. ErrS3TestEvent is returned when the message is
+// an s3 test event.
+//
+// A message that is itself an sns notification, as happens when sns messages
+// are forwarded through another topic or queue with raw delivery disabled, is
+// unwrapped to its inner message, up to maxSNSUnwrapDepth levels.
+func S3EventRecordsFromSNSWrapper(snsEvent events.SNSEvent) ([]*events.S3EventRecord, error) {
 	if len(snsEvent.Records) != 1 {
 		return nil, errors.New(fmt.Sprintf("expected only 1 SNS event, received: %v", len(snsEvent.Records)))
 	}
 
-	message := snsEvent.Records[0].SNS.Message
+	message, err := unwrapSNSMessage(snsEvent.Records[0].SNS.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	if CheckIfS3TestEvent(message) {
+		return nil, ErrS3TestEvent
+	}
 
 	s3Event := new(events.S3Event)
 	if err := json.Unmarshal([]byte(message), s3Event); err != nil {
 		return nil, errors.Wrapf(err, "failed to unmarshal %+v", s3Event)
 	}
 
-	if len(s3Event.Records) != 1 {
-		return nil, fmt.Errorf("expect only 1 S3 event, received: %v", len(s3Event.Records))
+	records := make([]*events.S3EventRecord, 0, len(s3Event.Records))
+	for i := range s3Event.Records {
+		records = append(records, &s3Event.Records[i])
+	}
+
+	return records, nil
+}
+
+// unwrapSNSMessage returns the innermost message of a message that is itself an
+// sns notification, i.e. has the 'Type' "Notification" and a 'Message'. Any
+// other message is returned as is.
+func unwrapSNSMessage(message string) (string, error) {
+	for depth := 0; ; depth++ {
+		var notification struct {
+			Type    string
+			Message *string
+		}
+
+		if err := json.Unmarshal([]byte(message), &notification); err != nil || notification.Type != "Notification" || notification.Message == nil {
+			return message, nil
+		}
+
+		if depth == maxSNSUnwrapDepth {
+			return "", errors.New(fmt.Sprintf("sns message is wrapped in more than %v sns notifications", maxSNSUnwrapDepth))
+		}
+
+		message = *notification.Message
+	}
+}
+
+// S3EventRecordFromSNSWrapper extracts the first underlying s3 event record
+// wrapped within the sns event. An error is returned when no s3 event records
+// are present.
+func S3EventRecordFromSNSWrapper(snsEvent events.SNSEvent) (*events.S3EventRecord, error) {
+	records, err := S3EventRecordsFromSNSWrapper(snsEvent)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("expected at least 1 S3 event, received: 0")
 	}
 
-	return &s3Event.Records[0], nil
+	return records[0], nil
+}
+
+// s3EventFromRecords builds an s3 event from the given records.
+func s3EventFromRecords(records ...*events.S3EventRecord) events.S3Event {
+	s3Event := events.S3Event{Records: make([]events.S3EventRecord, 0, len(records))}
+	for _, record := range records {
+		s3Event.Records = append(s3Event.Records, *record)
+	}
+
+	return s3Event
+}
+
+// UrisFromSNSS3EventMessage extracts the s3 uris of all the s3 event records
+// wrapped in the sns event.
+func UrisFromSNSS3EventMessage(snsEvent events.SNSEvent) ([]string, error) {
+	records, err := S3EventRecordsFromSNSWrapper(snsEvent)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed unwrapping s3 event records from sns")
+	}
+
+	return UriFromS3Event(s3EventFromRecords(records...))
 }
 
 // UriFromSNSS3EventMessage extracts the s3 uri from an s3 event wrapped
 // sns event.
 func UriFromSNSS3EventMessage(snsEvent events.SNSEvent) (string, error) {
-	b, k, err := S3ObjectFromSNSS3EventMessage(snsEvent)
+	record, err := S3EventRecordFromSNSWrapper(snsEvent)
 	if err != nil {
 		return "", errors.Wrap(err, "failed getting s3 bucket and key")
 	}
 
-	uri := fmt.Sprintf("s3://%s", path.Join(b, k))
-
-	if strings.HasSuffix(k, "/") {
-		uri = uri + "/"
+	uris, err := UriFromS3Event(s3EventFromRecords(record))
+	if err != nil {
+		return "", err
 	}
 
-	return uri, nil
+	return uris[0], nil
 }
 
 // S3ObjectFromSNSS3EventMessage extracts the bucket and key from an s3 event wrapped
-// sns event.
+// sns event. The key is url decoded.
 func S3ObjectFromSNSS3EventMessage(snsEvent events.SNSEvent) (string, string, error) {
 	record, err := S3EventRecordFromSNSWrapper(snsEvent)
 	if err != nil {
 		return "", "", errors.Wrap(err, "failed unwrapping s3 event record from sns")
 	}
 
-	return record.S3.Bucket.Name, record.S3.Object.Key, nil
+	objects, err := S3ObjectsFromS3Event(s3EventFromRecords(record))
+	if err != nil {
+		return "", "", err
+	}
+
+	return objects[0].Bucket, objects[0].Key, nil
+}
+
+// S3ObjectDetailsFromSNSS3EventMessage extracts the bucket, key, size and etag
+// from an s3 event wrapped sns event. The key is url decoded and the etag has
+// any surrounding quotes stripped. Multipart etags keep their '-N' suffix, where
+// N is the number of parts, as they are not an md5 of the object content.
+func S3ObjectDetailsFromSNSS3EventMessage(snsEvent events.SNSEvent) (S3ObjectDetails, error) {
+	record, err := S3EventRecordFromSNSWrapper(snsEvent)
+	if err != nil {
+		return S3ObjectDetails{}, errors.Wrap(err, "failed unwrapping s3 event record from sns")
+	}
+
+	key, err := decodeKey(record.S3.Object.Key)
+	if err != nil {
+		return S3ObjectDetails{}, err
+	}
+
+	details := S3ObjectDetails{
+		S3Object: S3Object{Bucket: record.S3.Bucket.Name, Key: key},
+		Size:     record.S3.Object.Size,
+		ETag:     cleanETag(record.S3.Object.ETag),
+	}
+
+	return details, nil
+}
+
+// S3VersionedObjectFromSNSS3EventMessage extracts the bucket, key and version id
+// from an s3 event wrapped sns event. The version id is empty for unversioned
+// objects.
+func S3VersionedObjectFromSNSS3EventMessage(snsEvent events.SNSEvent) (string, string, string, error) {
+	record, err := S3EventRecordFromSNSWrapper(snsEvent)
+	if err != nil {
+		return "", "", "", errors.Wrap(err, "failed unwrapping s3 event record from sns")
+	}
+
+	key, err := decodeKey(record.S3.Object.Key)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return record.S3.Bucket.Name, key, record.S3.Object.VersionID, nil
+}
+
+// VersionedUriFromSNSS3EventMessage extracts the s3 uri, including the version
+// id in the form 's3://bucket/key?versionId=...', from an s3 event wrapped sns
+// event. The version id query is omitted for unversioned objects.
+func VersionedUriFromSNSS3EventMessage(snsEvent events.SNSEvent) (string, error) {
+	b, k, v, err := S3VersionedObjectFromSNSS3EventMessage(snsEvent)
+	if err != nil {
+		return "", errors.Wrap(err, "failed getting s3 bucket, key and version")
+	}
+
+	if v == "" {
+		return uri(b, k), nil
+	}
+
+	return fmt.Sprintf("%s?versionId=%s", uri(b, k), url.QueryEscape(v)), nil
+}
+
+// S3ObjectArnFromSNSS3EventMessage builds the object arn, in the form
+// 'arn:<partition>:s3:::<bucket>/<key>', from an s3 event wrapped sns event.
+// The key is url decoded. The partition is optional and defaults to 'aws', pass
+// e.g. 'aws-cn' or 'aws-us-gov' for objects in other partitions.
+func S3ObjectArnFromSNSS3EventMessage(snsEvent events.SNSEvent, partition ...string) (string, error) {
+	if len(partition) > 1 {
+		return "", errors.New(fmt.Sprintf("expected at most 1 partition, received: %v", len(partition)))
+	}
+
+	p := "aws"
+	if len(partition) == 1 && partition[0] != "" {
+		p = partition[0]
+	}
+
+	b, k, err := S3ObjectFromSNSS3EventMessage(snsEvent)
+	if err != nil {
+		return "", errors.Wrap(err, "failed getting s3 bucket and key")
+	}
+
+	return fmt.Sprintf("arn:%s:s3:::%s/%s", p, b, k), nil
+}
+
+// EventTimeFromSNSS3EventMessage extracts the time of the s3 event, such as
+// when the object was created, from an s3 event wrapped sns event. S3 sends the
+// time as RFC3339 with milliseconds, e.g. '2018-07-12T16:26:25.733Z'. An error
+// is returned if the time can't be parsed or is missing.
+func EventTimeFromSNSS3EventMessage(snsEvent events.SNSEvent) (time.Time, error) {
+	record, err := S3EventRecordFromSNSWrapper(snsEvent)
+	var perr *time.ParseError
+	if errors.As(err, &perr) {
+		return time.Time{}, errors.Wrapf(err, "failed parsing s3 eventTime '%s', expected RFC3339", perr.Value)
+	}
+
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed unwrapping s3 event record from sns")
+	}
+
+	if record.EventTime.IsZero() {
+		return time.Time{}, errors.New("s3 event record has no eventTime")
+	}
+
+	return record.EventTime, nil
 }
@@ -1,8 +1,11 @@
 package s3eventutils
 
 import (
+	"encoding/json"
+	"errors"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/stretchr/testify/assert"
@@ -40,6 +43,47 @@ func Test_S3EventRecordFromSNSWrapper(t *testing.T) {
 	assert.Equal(t, "some/file/in/s3.txt", r.S3.Object.Key)
 }
 
+// wrapSNSNotification wraps the message in an sns notification, as a message
+// forwarded through another sns topic without raw delivery would be.
+func wrapSNSNotification(t *testing.T, message string) string {
+	b, err := json.Marshal(createSNSRecord(message).SNS)
+	assert.NoError(t, err)
+
+	return string(b)
+}
+
+func Test_S3EventRecordFromSNSWrapper_doubleWrapped(t *testing.T) {
+	b, err := os.ReadFile("testdata/valid_message_s3.json")
+	assert.NoError(t, err)
+
+	messages := []string{
+		string(b),
+		wrapSNSNotification(t, string(b)),
+		wrapSNSNotification(t, wrapSNSNotification(t, string(b))),
+	}
+
+	for _, message := range messages {
+		r, err := S3EventRecordFromSNSWrapper(createSNSEvent(createSNSRecord(message)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "bktname", r.S3.Bucket.Name)
+		assert.Equal(t, "some/file/in/s3.txt", r.S3.Object.Key)
+	}
+}
+
+func Test_S3EventRecordFromSNSWrapper_error_wrapDepth(t *testing.T) {
+	b, err := os.ReadFile("testdata/valid_message_s3.json")
+	assert.NoError(t, err)
+
+	message := string(b)
+	for i := 0; i <= maxSNSUnwrapDepth; i++ {
+		message = wrapSNSNotification(t, message)
+	}
+
+	_, err = S3EventRecordFromSNSWrapper(createSNSEvent(createSNSRecord(message)))
+	assert.EqualError(t, err, "sns message is wrapped in more than 3 sns notifications")
+}
+
 func Test_S3EventRecordFromSNSWrapper_error_sns_record_count(t *testing.T) {
 	b, err := os.ReadFile("testdata/valid_message_s3.json")
 	assert.NoError(t, err)
@@ -57,13 +101,85 @@ func Test_S3EventRecordFromSNSWrapper_error_invalid_message(t *testing.T) {
 	assert.Error(t, err)
 }
 
-func Test_S3EventRecordFromSNSWrapper_error_s3_Record_count(t *testing.T) {
+func Test_S3EventRecordFromSNSWrapper_multiple(t *testing.T) {
 	b, err := os.ReadFile("testdata/invalid_message_s3_count.json")
 	assert.NoError(t, err)
 
 	snsEvent := createSNSEvent(createSNSRecord(string(b)))
 
+	r, err := S3EventRecordFromSNSWrapper(snsEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, "some/file/in/s3.txt", r.S3.Object.Key)
+}
+
+func Test_S3EventRecordFromSNSWrapper_error_s3_Record_count(t *testing.T) {
+	snsEvent := createSNSEvent(createSNSRecord(`{"Records": []}`))
+
+	_, err := S3EventRecordFromSNSWrapper(snsEvent)
+	assert.Error(t, err)
+}
+
+func Test_S3EventRecordsFromSNSWrapper_testEvent(t *testing.T) {
+	b, err := os.ReadFile("testdata/s3_test_event.json")
+	assert.NoError(t, err)
+
+	snsEvent := createSNSEvent(createSNSRecord(string(b)))
+
+	_, err = S3EventRecordsFromSNSWrapper(snsEvent)
+	assert.True(t, errors.Is(err, ErrS3TestEvent))
+
 	_, err = S3EventRecordFromSNSWrapper(snsEvent)
+	assert.True(t, errors.Is(err, ErrS3TestEvent))
+
+	_, err = UriFromSNSS3EventMessage(snsEvent)
+	assert.True(t, errors.Is(err, ErrS3TestEvent))
+
+	_, err = S3EventRecordsFromSNSWrapper(createSNSEvent(createSNSRecord("not json")))
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrS3TestEvent))
+}
+
+func Test_S3EventRecordsFromSNSWrapper(t *testing.T) {
+	b, err := os.ReadFile("testdata/invalid_message_s3_count.json")
+	assert.NoError(t, err)
+
+	snsEvent := createSNSEvent(createSNSRecord(string(b)))
+
+	records, err := S3EventRecordsFromSNSWrapper(snsEvent)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "some/file/in/s3.txt", records[0].S3.Object.Key)
+	assert.Equal(t, "some/file/in/s3-2.txt", records[1].S3.Object.Key)
+}
+
+func Test_S3EventRecordsFromSNSWrapper_empty(t *testing.T) {
+	snsEvent := createSNSEvent(createSNSRecord(`{"Records": []}`))
+
+	records, err := S3EventRecordsFromSNSWrapper(snsEvent)
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func Test_S3EventRecordsFromSNSWrapper_error_sns_record_count(t *testing.T) {
+	_, err := S3EventRecordsFromSNSWrapper(createSNSEvent())
+	assert.Error(t, err)
+}
+
+func TestUrisFromSNSS3EventMessage(t *testing.T) {
+	b, err := os.ReadFile("testdata/valid_message_s3_multi.json")
+	assert.NoError(t, err)
+
+	snsEvent := createSNSEvent(createSNSRecord(string(b)))
+
+	uris, err := UrisFromSNSS3EventMessage(snsEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"s3://bktname/some/file/in/s3.txt", "s3://bktname2/some/file/in/my folder/"}, uris)
+}
+
+func TestUrisFromSNSS3EventMessage_error(t *testing.T) {
+	snsEvent := createSNSEvent(createSNSRecord("not json"))
+
+	_, err := UrisFromSNSS3EventMessage(snsEvent)
 	assert.Error(t, err)
 }
 
@@ -114,3 +230,191 @@ func TestS3ObjectFromSNSS3EventMessage_error(t *testing.T) {
 	_, _, err := S3ObjectFromSNSS3EventMessage(snsEvent)
 	assert.Error(t, err)
 }
+
+func TestUriFromSNSS3EventMessage_encoded(t *testing.T) {
+	b, err := os.ReadFile("testdata/valid_message_s3_encoded.json")
+	assert.NoError(t, err)
+
+	snsEvent := createSNSEvent(createSNSRecord(string(b)))
+
+	uri, err := UriFromSNSS3EventMessage(snsEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, "s3://bktname/some/file/in/my report+2018(final).pdf", uri)
+}
+
+func TestS3ObjectFromSNSS3EventMessage_encoded(t *testing.T) {
+	b, err := os.ReadFile("testdata/valid_message_s3_encoded.json")
+	assert.NoError(t, err)
+
+	snsEvent := createSNSEvent(createSNSRecord(string(b)))
+
+	bucket, key, err := S3ObjectFromSNSS3EventMessage(snsEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, "bktname", bucket)
+	assert.Equal(t, "some/file/in/my report+2018(final).pdf", key)
+}
+
+func TestS3ObjectFromSNSS3EventMessage_error_key(t *testing.T) {
+	b, err := os.ReadFile("testdata/invalid_message_s3_key.json")
+	assert.NoError(t, err)
+
+	snsEvent := createSNSEvent(createSNSRecord(string(b)))
+
+	_, _, err = S3ObjectFromSNSS3EventMessage(snsEvent)
+	assert.Error(t, err)
+}
+
+func TestS3ObjectDetailsFromSNSS3EventMessage(t *testing.T) {
+	cases := []struct {
+		file         string
+		expectedSize int64
+		expectedETag string
+	}{
+		{"testdata/valid_message_s3.json", 1202, "f81ea34505f2bd6e9131072351093e20"},
+		{"testdata/valid_message_s3_etag.json", 1202, "f81ea34505f2bd6e9131072351093e20"},
+		{"testdata/valid_message_s3_etag_multipart.json", 15728640, "d41d8cd98f00b204e9800998ecf8427e-3"},
+	}
+
+	for _, c := range cases {
+		b, err := os.ReadFile(c.file)
+		assert.NoError(t, err)
+
+		snsEvent := createSNSEvent(createSNSRecord(string(b)))
+
+		details, err := S3ObjectDetailsFromSNSS3EventMessage(snsEvent)
+		assert.NoError(t, err)
+		assert.Equal(t, "bktname", details.Bucket)
+		assert.Equal(t, "some/file/in/s3.txt", details.Key)
+		assert.Equal(t, c.expectedSize, details.Size)
+		assert.Equal(t, c.expectedETag, details.ETag)
+	}
+}
+
+func TestS3ObjectDetailsFromSNSS3EventMessage_error(t *testing.T) {
+	snsEvent := createSNSEvent(createSNSRecord("not json"))
+
+	_, err := S3ObjectDetailsFromSNSS3EventMessage(snsEvent)
+	assert.Error(t, err)
+}
+
+func TestS3VersionedObjectFromSNSS3EventMessage(t *testing.T) {
+	cases := []struct {
+		file            string
+		expectedVersion string
+	}{
+		{"testdata/valid_message_s3.json", ""},
+		{"testdata/valid_message_s3_versioned.json", "3HL4kqtJlcpXroDTDmjVBH40Nrjfkd"},
+	}
+
+	for _, c := range cases {
+		b, err := os.ReadFile(c.file)
+		assert.NoError(t, err)
+
+		snsEvent := createSNSEvent(createSNSRecord(string(b)))
+
+		bucket, key, version, err := S3VersionedObjectFromSNSS3EventMessage(snsEvent)
+		assert.NoError(t, err)
+		assert.Equal(t, "bktname", bucket)
+		assert.Equal(t, "some/file/in/s3.txt", key)
+		assert.Equal(t, c.expectedVersion, version)
+	}
+}
+
+func TestS3VersionedObjectFromSNSS3EventMessage_error(t *testing.T) {
+	snsEvent := createSNSEvent(createSNSRecord("not json"))
+
+	_, _, _, err := S3VersionedObjectFromSNSS3EventMessage(snsEvent)
+	assert.Error(t, err)
+}
+
+func TestVersionedUriFromSNSS3EventMessage(t *testing.T) {
+	cases := []struct {
+		file        string
+		expectedUri string
+	}{
+		{"testdata/valid_message_s3.json", "s3://bktname/some/file/in/s3.txt"},
+		{"testdata/valid_message_s3_versioned.json", "s3://bktname/some/file/in/s3.txt?versionId=3HL4kqtJlcpXroDTDmjVBH40Nrjfkd"},
+	}
+
+	for _, c := range cases {
+		b, err := os.ReadFile(c.file)
+		assert.NoError(t, err)
+
+		snsEvent := createSNSEvent(createSNSRecord(string(b)))
+
+		uri, err := VersionedUriFromSNSS3EventMessage(snsEvent)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expectedUri, uri)
+	}
+}
+
+func TestVersionedUriFromSNSS3EventMessage_error(t *testing.T) {
+	snsEvent := createSNSEvent(createSNSRecord("not json"))
+
+	_, err := VersionedUriFromSNSS3EventMessage(snsEvent)
+	assert.Error(t, err)
+}
+
+func TestS3ObjectArnFromSNSS3EventMessage(t *testing.T) {
+	cases := []struct {
+		file        string
+		partition   []string
+		expectedArn string
+	}{
+		{"testdata/valid_message_s3.json", nil, "arn:aws:s3:::bktname/some/file/in/s3.txt"},
+		{"testdata/valid_message_s3.json", []string{""}, "arn:aws:s3:::bktname/some/file/in/s3.txt"},
+		{"testdata/valid_message_s3.json", []string{"aws-cn"}, "arn:aws-cn:s3:::bktname/some/file/in/s3.txt"},
+		{"testdata/valid_message_s3.json", []string{"aws-us-gov"}, "arn:aws-us-gov:s3:::bktname/some/file/in/s3.txt"},
+		{"testdata/valid_message_s3_encoded.json", nil, "arn:aws:s3:::bktname/some/file/in/my report+2018(final).pdf"},
+	}
+
+	for _, c := range cases {
+		b, err := os.ReadFile(c.file)
+		assert.NoError(t, err)
+
+		snsEvent := createSNSEvent(createSNSRecord(string(b)))
+
+		arn, err := S3ObjectArnFromSNSS3EventMessage(snsEvent, c.partition...)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expectedArn, arn)
+	}
+}
+
+func TestS3ObjectArnFromSNSS3EventMessage_error(t *testing.T) {
+	snsEvent := createSNSEvent(createSNSRecord("not json"))
+
+	_, err := S3ObjectArnFromSNSS3EventMessage(snsEvent)
+	assert.Error(t, err)
+
+	b, err := os.ReadFile("testdata/valid_message_s3.json")
+	assert.NoError(t, err)
+
+	_, err = S3ObjectArnFromSNSS3EventMessage(createSNSEvent(createSNSRecord(string(b))), "aws", "aws-cn")
+	assert.EqualError(t, err, "expected at most 1 partition, received: 2")
+}
+
+func TestEventTimeFromSNSS3EventMessage(t *testing.T) {
+	b, err := os.ReadFile("testdata/valid_message_s3_event_time.json")
+	assert.NoError(t, err)
+
+	eventTime, err := EventTimeFromSNSS3EventMessage(createSNSEvent(createSNSRecord(string(b))))
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 11, 2, 21, 4, 17, 812000000, time.UTC), eventTime.UTC())
+}
+
+func TestEventTimeFromSNSS3EventMessage_error(t *testing.T) {
+	b, err := os.ReadFile("testdata/invalid_message_s3_event_time.json")
+	assert.NoError(t, err)
+
+	_, err = EventTimeFromSNSS3EventMessage(createSNSEvent(createSNSRecord(string(b))))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed parsing s3 eventTime '2023-11-02 21:04:17', expected RFC3339")
+
+	_, err = EventTimeFromSNSS3EventMessage(createSNSEvent(createSNSRecord("not json")))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed unwrapping s3 event record from sns")
+
+	_, err = EventTimeFromSNSS3EventMessage(createSNSEvent(createSNSRecord(`{"Records":[{"eventName":"ObjectCreated:Put"}]}`)))
+	assert.EqualError(t, err, "s3 event record has no eventTime")
+}
@@ -1,6 +1,14 @@
 package s3eventutils
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// ErrS3TestEvent is returned when unwrapping an s3 test event, which s3 sends
+// when a bucket notification is first configured.
+var ErrS3TestEvent = errors.New("s3 test event")
 
 // S3TestEvent ...
 type S3TestEvent struct {